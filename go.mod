@@ -8,6 +8,7 @@ require (
 	github.com/charmbracelet/bubbletea v0.20.0
 	github.com/charmbracelet/lipgloss v0.7.1
 	github.com/go-git/go-git/v5 v5.11.0
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/lucasb-eyer/go-colorful v1.2.0
 	github.com/muesli/termenv v0.15.1
 	github.com/nilock/walk-repo v0.1.0
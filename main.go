@@ -1,9 +1,24 @@
 package main
 
 import (
+	"os"
+
 	"github.com/nilock/tuido/tui"
 )
 
 func main() {
-	tui.Run()
+	args := os.Args[1:]
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "archive":
+			os.Exit(tui.ArchiveCommand(args[1:]))
+		case "report":
+			os.Exit(tui.ReportCommand(args[1:]))
+		case "migrate":
+			os.Exit(tui.MigrateCommand(args[1:]))
+		}
+	}
+
+	tui.Run(args)
 }
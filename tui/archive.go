@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+// archivePath returns the archive file for items completed at t.
+func (cfg config) archivePath(t time.Time) string {
+	return strings.NewReplacer(
+		"YYYY", t.Format("2006"),
+		"MM", t.Format("01"),
+		"DD", t.Format("02"),
+	).Replace(expandHome(cfg.archive))
+}
+
+// archiveGlob is the archive setting as a filepath.Match pattern.
+func (cfg config) archiveGlob() string {
+	return strings.NewReplacer(
+		"YYYY", "[0-9][0-9][0-9][0-9]",
+		"MM", "[0-9][0-9]",
+		"DD", "[0-9][0-9]",
+	).Replace(expandHome(cfg.archive))
+}
+
+// isArchiveFile reports whether path is one of the configured archive files.
+func (cfg config) isArchiveFile(path string) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	pattern, err := filepath.Abs(cfg.archiveGlob())
+	if err != nil {
+		return false
+	}
+	match, _ := filepath.Match(pattern, abs)
+	return match
+}
+
+// archiveFiles returns the existing archive files.
+func (cfg config) archiveFiles() []string {
+	files, _ := filepath.Glob(cfg.archiveGlob())
+	return files
+}
+
+// expandHome replaces a leading ~ in path with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+func archivable(item *tuido.Item) bool {
+	return item.Satus() == tuido.Checked || item.Satus() == tuido.Obsolete
+}
+
+// archive moves a done item to the end of its archive file, chosen by the
+// item's completion date. Items without one are dated today, and the date
+// is recorded on the archived item.
+func archive(item *tuido.Item, cfg config, items []*tuido.Item) error {
+	if !archivable(item) {
+		return fmt.Errorf("only done items can be archived")
+	}
+	if cfg.isArchiveFile(item.File()) {
+		return fmt.Errorf("item is already archived")
+	}
+
+	completed := time.Now()
+	if c := item.Completed(); c != nil {
+		completed = *c
+	} else if err := item.SetCompleted(completed); err != nil {
+		return err
+	}
+
+	return item.RefileTo(cfg.archivePath(completed), nil, items)
+}
+
+// archiveAll archives those of candidates that are done and were completed
+// before cutoff. Items without a completion date are only archived when
+// cutoff is nil, ie, when archiving everything. items are all in-memory
+// items, whose line numbers are kept in step. It returns the number archived.
+func archiveAll(candidates, items []*tuido.Item, cfg config, cutoff *time.Time) (int, error) {
+	archived := 0
+
+	for _, item := range candidates {
+		if !cfg.archiveDue(item, cutoff) {
+			continue
+		}
+
+		if err := archive(item, cfg, items); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+
+	return archived, nil
+}
+
+// archiveDue reports whether archiveAll archives item, given cutoff.
+func (cfg config) archiveDue(item *tuido.Item, cutoff *time.Time) bool {
+	if !archivable(item) || cfg.isArchiveFile(item.File()) {
+		return false
+	}
+	if cutoff == nil {
+		return true
+	}
+	c := item.Completed()
+	return c != nil && c.Before(*cutoff)
+}
+
+// archiveCutoff converts an age like 30d into the completion time before
+// which items are old enough to be archived, counting back from now. An
+// age is a whole number of hours (h), days (d), weeks (w), months (M),
+// or years (y). Anything else is an error, so that a mistyped age never
+// archives everything.
+func archiveCutoff(age string, now time.Time) (*time.Time, error) {
+	if age == "" {
+		return nil, nil
+	}
+
+	invalid := fmt.Errorf("invalid archive age %q: expected a whole number of hours (h), days (d), weeks (w), months (M), or years (y), eg 30d", age)
+	num, err := strconv.Atoi(age[:len(age)-1])
+	if err != nil || num < 1 {
+		return nil, invalid
+	}
+
+	var cutoff time.Time
+	switch age[len(age)-1] {
+	case 'h':
+		cutoff = now.Add(-time.Duration(num) * time.Hour)
+	case 'd':
+		cutoff = now.AddDate(0, 0, -num)
+	case 'w':
+		cutoff = now.AddDate(0, 0, -7*num)
+	case 'M':
+		cutoff = now.AddDate(0, -num, 0)
+	case 'y':
+		cutoff = now.AddDate(-num, 0, 0)
+	default:
+		return nil, invalid
+	}
+	return &cutoff, nil
+}
+
+// archiveSelection archives the current selection.
+func (t *tui) archiveSelection() {
+	item := t.currentSelection()
+	if item == nil {
+		return
+	}
+
+	t.stopClockOn(item)
+	t.err = archive(item, t.config, t.items)
+	if t.err == nil {
+		t.dropArchived([]*tuido.Item{item})
+	}
+}
+
+// archiveListed archives every done item in the current list.
+func (t *tui) archiveListed() {
+	if t.itemsFilter != done {
+		return
+	}
+
+	listed := t.renderSelection
+	t.stopClockBeforeArchiving(listed, nil)
+	count, err := archiveAll(listed, t.items, t.config, nil)
+	t.err = err
+	t.dropArchived(listed)
+	t.notifs = append(t.notifs, fmt.Sprintf("Archived %d item(s)", count))
+}
+
+// autoArchive archives done items older than the configured archiveAfter age.
+func (t *tui) autoArchive() {
+	cutoff, err := archiveCutoff(t.config.archiveAfter, time.Now())
+	if err != nil {
+		t.notifs = append(t.notifs, err.Error())
+		return
+	}
+	if cutoff == nil {
+		return
+	}
+
+	t.stopClockBeforeArchiving(t.items, cutoff)
+	count, err := archiveAll(t.items, t.items, t.config, cutoff)
+	t.dropArchived(t.items)
+	if err != nil {
+		t.notifs = append(t.notifs, fmt.Sprintf("error archiving items: %s", err))
+	}
+	if count > 0 {
+		t.notifs = append(t.notifs, fmt.Sprintf("Archived %d item(s) completed over %s ago", count, t.config.archiveAfter))
+	}
+}
+
+// stopClockBeforeArchiving stops the clock if its item is one of the
+// candidates that archiveAll is about to archive.
+func (t *tui) stopClockBeforeArchiving(candidates []*tuido.Item, cutoff *time.Time) {
+	if t.clock == nil || !t.config.archiveDue(t.clock.item, cutoff) {
+		return
+	}
+	for _, item := range candidates {
+		if item == t.clock.item {
+			t.stopClock(time.Now())
+			return
+		}
+	}
+}
+
+// dropArchived removes those of candidates that are now archived from the
+// item list, unless archives are being browsed.
+func (t *tui) dropArchived(candidates []*tuido.Item) {
+	if t.includeArchives {
+		return
+	}
+
+	archived := map[*tuido.Item]struct{}{}
+	for _, item := range candidates {
+		if t.config.isArchiveFile(item.File()) {
+			archived[item] = struct{}{}
+		}
+	}
+
+	kept := []*tuido.Item{}
+	for _, item := range t.items {
+		if _, ok := archived[item]; !ok {
+			kept = append(kept, item)
+		}
+	}
+	t.items = kept
+	t.populateRenderSelection()
+}
+
+// ArchiveCommand implements `tuido archive`, which archives all done items
+// found from the working directory. It returns the process exit code.
+func ArchiveCommand(args []string) int {
+	flags := flag.NewFlagSet("tuido archive", flag.ExitOnError)
+	olderThan := flags.String("older-than", "", "only archive items completed longer ago than this (eg, 30d, 2w)")
+	flags.Parse(args)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	adoptConfigSettings(filepath.Join(wd, ".tuido"))
+
+	cutoff, err := archiveCutoff(*olderThan, time.Now())
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	_, items := scan(wd, false)
+	count, err := archiveAll(items, items, runConfig, cutoff)
+	fmt.Printf("archived %d item(s)\n", count)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestArchiveCutoff(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+
+	tests := map[string]time.Time{
+		"12h": time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local),
+		"30d": time.Date(2026, 9, 19, 12, 0, 0, 0, time.Local),
+		"2w":  time.Date(2026, 10, 5, 12, 0, 0, 0, time.Local),
+		"6M":  time.Date(2026, 4, 19, 12, 0, 0, 0, time.Local),
+		"2y":  time.Date(2024, 10, 19, 12, 0, 0, 0, time.Local),
+	}
+	for age, expected := range tests {
+		cutoff, err := archiveCutoff(age, now)
+		if err != nil {
+			t.Errorf("archiveCutoff(%q): unexpected error %v", age, err)
+			continue
+		}
+		if !cutoff.Equal(expected) {
+			t.Errorf("archiveCutoff(%q): expected %s, but found %s", age, expected, cutoff)
+		}
+	}
+
+	if cutoff, err := archiveCutoff("", now); cutoff != nil || err != nil {
+		t.Errorf("expected no cutoff for no age, but found %v, %v", cutoff, err)
+	}
+	for _, age := range []string{"6m", "1D", "30x", "d", "0d", "-3d", "1.5w", "thirty days"} {
+		if cutoff, err := archiveCutoff(age, now); cutoff != nil || err == nil {
+			t.Errorf("archiveCutoff(%q): expected an error, but found %v, %v", age, cutoff, err)
+		}
+	}
+}
+
+func TestAutoArchiveBadAge(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "list.xit")
+	contents := "[x] done long ago #completed=2020-01-01\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+	item := tuido.New(file, 1, "[x] done long ago #completed=2020-01-01")
+
+	cfg := runConfig
+	cfg.archive = filepath.Join(dir, "archive", "YYYY.xit")
+	cfg.archiveAfter = "30x"
+	model := tui{config: cfg, items: []*tuido.Item{&item}}
+	model.autoArchive()
+
+	if got, _ := os.ReadFile(file); string(got) != contents {
+		t.Errorf("expected nothing archived for a bad age, but found %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "archive")); !os.IsNotExist(err) {
+		t.Errorf("expected no archive files for a bad age, but found %v", err)
+	}
+	if len(model.notifs) != 1 {
+		t.Errorf("expected the bad age to be reported, but found %v", model.notifs)
+	}
+}
@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// stopwatch is an open-ended clock of the time spent on an item. Only
+// one runs at a time, and it keeps running across sessions: it is saved
+// to the appState when tuido closes, and resumed on the next launch.
+type stopwatch struct {
+	item  *tuido.Item
+	start time.Time
+}
+
+// toggleClock starts the clock on the selected item, or stops it if it
+// is already running there. Starting the clock on one item stops it on
+// any other.
+func (t *tui) toggleClock() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil {
+		return nil
+	}
+
+	if t.clock != nil {
+		stopped := t.clock.item
+		t.stopClock(time.Now())
+		if stopped == item {
+			return nil
+		}
+	}
+
+	t.clock = &stopwatch{item: item, start: time.Now()}
+	return t.keepTicking()
+}
+
+// stopClock credits the time on the clock to its item, and records it
+// in the session log.
+func (t *tui) stopClock(now time.Time) {
+	c := t.clock
+	if c == nil {
+		return
+	}
+	t.clock = nil
+
+	elapsed := now.Sub(c.start)
+	if elapsed < time.Second {
+		return
+	}
+
+	err := appendSession(sessionsPath, session{
+		item:   c.item.Location(),
+		text:   c.item.Text(),
+		start:  c.start,
+		end:    now,
+		actual: elapsed,
+	})
+	if err != nil {
+		t.err = fmt.Errorf("error logging clocked time: %w", err)
+	}
+	if err := c.item.IncrementTimeSpent(int(elapsed.Seconds())); err != nil {
+		t.err = fmt.Errorf("error recording clocked time: %w", err)
+	}
+}
+
+// stopClockOn stops the clock if it is running on item, crediting its
+// time before item is deleted or archived.
+func (t *tui) stopClockOn(item *tuido.Item) {
+	if t.clock != nil && t.clock.item == item {
+		t.stopClock(time.Now())
+	}
+}
+
+// creditClockOn credits the time on the clock so far if it is running on
+// item, before item is moved to another file. The clock keeps running on
+// the moved item.
+func (t *tui) creditClockOn(item *tuido.Item) {
+	if t.clock == nil || t.clock.item != item {
+		return
+	}
+	now := time.Now()
+	t.stopClock(now)
+	t.clock = &stopwatch{item: item, start: now}
+}
+
+// save records the running clock, if any, in st, so that it resumes on
+// the next launch.
+func (c *stopwatch) save(st *appState) {
+	st.clockStart, st.clockItem, st.clockText = time.Time{}, "", ""
+	if c == nil {
+		return
+	}
+	st.clockStart = c.start
+	st.clockItem = c.item.Location()
+	st.clockText = c.item.Text()
+}
+
+// resumeClock restarts the clock saved in the appState when tuido last
+// closed, finding its item by location, or else by its text.
+func (t *tui) resumeClock() {
+	st := t.state
+	if st.clockStart.IsZero() {
+		return
+	}
+
+	var found *tuido.Item
+	for _, item := range t.items {
+		if item.Text() != st.clockText {
+			continue
+		}
+		if found == nil || item.Location() == st.clockItem {
+			found = item
+		}
+	}
+
+	if found == nil {
+		t.notifs = append(t.notifs, fmt.Sprintf(
+			"The clock was running on an item that can no longer be found, since %s:\n  %s\nThat time was not recorded.",
+			st.clockStart.Format("Mon Jan 2 15:04"), st.clockText))
+		return
+	}
+	t.clock = &stopwatch{item: found, start: st.clockStart}
+}
+
+// clockView shows the running clock and its item, for the header.
+func (t tui) clockView() string {
+	if t.clock == nil {
+		return ""
+	}
+
+	txt := []rune(t.clock.item.Text())
+	if len(txt) > 24 {
+		txt = append(txt[:23], '…')
+	}
+
+	return clockStyle.Render("● "+formatElapsed(time.Since(t.clock.start))) +
+		lg.NewStyle().Faint(true).Render(" "+string(txt))
+}
+
+var clockStyle = lg.NewStyle().Bold(true).Foreground(lg.Color("#ff8855"))
+
+// formatElapsed formats a running time as h:mm:ss.
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// maxSuggestions is the number of completions shown at once.
+const maxSuggestions = 6
+
+// builtinTags are the tags that tuido itself interprets, with hints
+// describing their values.
+var builtinTags = map[string]string{
+	"due":      "due date: 2026-11-03, 3d, friday",
+	"active":   "hidden until: 2026-11-03, 3d, tomorrow 9am",
+	"repeat":   "repeats every: 1d, 1w, 1m",
+	"estimate": "expected time: 25m, 2h",
+	"spent":    "time spent so far: 45m, 1h15m",
+	"planned":  "planned for the day: 2026-11-03",
+}
+
+// suggestion is a completion for the tag being typed.
+type suggestion struct {
+	// text replaces the partially typed tag
+	text string
+	hint string
+}
+
+// tagCompleter suggests completions for #tags typed in the item editor,
+// from the tags already used across all items.
+type tagCompleter struct {
+	// names counts the uses of each tag, and values the uses of each value by tag
+	names  map[string]int
+	values map[string]map[string]int
+
+	suggestions []suggestion
+	selected    int
+}
+
+func newTagCompleter(items []*tuido.Item) tagCompleter {
+	c := tagCompleter{
+		names:  map[string]int{},
+		values: map[string]map[string]int{},
+	}
+	for name := range builtinTags {
+		c.names[name] = 0
+	}
+
+	for _, item := range items {
+		for _, tag := range item.Tags() {
+			c.names[tag.Name()]++
+			if tag.Value() == "" {
+				continue
+			}
+			if c.values[tag.Name()] == nil {
+				c.values[tag.Name()] = map[string]int{}
+			}
+			c.values[tag.Name()][tag.Value()]++
+		}
+	}
+	return c
+}
+
+// partialTag returns the start of the word being typed at cursor in txt,
+// and that word, if it is a #tag. Positions count runes, as the
+// editor's cursor does.
+func partialTag(txt string, cursor int) (start int, tag string, ok bool) {
+	r := []rune(txt)
+	cursor = min(cursor, len(r))
+	start = cursor
+	for start > 0 && r[start-1] != ' ' {
+		start--
+	}
+	tag = string(r[start:cursor])
+	return start, tag, strings.HasPrefix(tag, "#")
+}
+
+// update refreshes the suggestions for the editor's text and cursor.
+func (c *tagCompleter) update(txt string, cursor int) {
+	c.suggestions = nil
+	c.selected = 0
+
+	_, partial, ok := partialTag(txt, cursor)
+	if !ok {
+		return
+	}
+	partial = strings.TrimPrefix(partial, "#")
+
+	if name, value, hasValue := strings.Cut(partial, "="); hasValue {
+		for _, v := range rank(c.values[name], value) {
+			if v != value {
+				c.suggestions = append(c.suggestions, suggestion{text: "#" + name + "=" + v + " "})
+			}
+		}
+		if len(c.suggestions) == 0 && value == "" {
+			if hint, ok := builtinTags[name]; ok {
+				c.suggestions = append(c.suggestions, suggestion{text: "#" + name + "=", hint: hint})
+			}
+		}
+		return
+	}
+
+	for _, name := range rank(c.names, partial) {
+		text := "#" + name + " "
+		if _, builtin := builtinTags[name]; builtin || len(c.values[name]) > 0 {
+			text = "#" + name + "="
+		}
+		c.suggestions = append(c.suggestions, suggestion{text: text, hint: builtinTags[name]})
+	}
+	// the tag is already complete
+	if len(c.suggestions) == 1 && strings.TrimRight(c.suggestions[0].text, "= ") == "#"+partial {
+		c.suggestions = nil
+	}
+}
+
+// rank returns the keys of counts beginning with prefix, most used first.
+func rank(counts map[string]int, prefix string) []string {
+	ranked := []string{}
+	for k := range counts {
+		if strings.HasPrefix(k, prefix) {
+			ranked = append(ranked, k)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if counts[ranked[i]] != counts[ranked[j]] {
+			return counts[ranked[i]] > counts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
+
+func (c *tagCompleter) move(step int) {
+	if len(c.suggestions) == 0 {
+		return
+	}
+	c.selected = (c.selected + step + len(c.suggestions)) % len(c.suggestions)
+}
+
+// complete applies the selected suggestion to txt, returning the
+// updated text and cursor position.
+func (c *tagCompleter) complete(txt string, cursor int) (string, int) {
+	if len(c.suggestions) == 0 {
+		return txt, cursor
+	}
+	start, _, _ := partialTag(txt, cursor)
+	text := c.suggestions[c.selected].text
+
+	r := []rune(txt)
+	rest := string(r[min(cursor, len(r)):])
+	if strings.HasPrefix(rest, " ") {
+		text = strings.TrimSuffix(text, " ")
+	}
+	return string(r[:start]) + text + rest, start + len([]rune(text))
+}
+
+func (c tagCompleter) View() string {
+	if len(c.suggestions) == 0 {
+		return ""
+	}
+
+	faint := lg.NewStyle().Faint(true)
+
+	// keep the selected suggestion in view
+	first := max(0, c.selected-maxSuggestions+1)
+	last := min(len(c.suggestions), first+maxSuggestions)
+
+	rows := []string{}
+	for i := first; i < last; i++ {
+		s := c.suggestions[i]
+		row := pickerRow(s.text, i == c.selected)
+		if s.hint != "" {
+			row += "  " + faint.Render(s.hint)
+		}
+		rows = append(rows, row)
+	}
+	if len(c.suggestions) > last {
+		rows = append(rows, faint.Render("  ... [up]/[down] for more, [tab] to complete"))
+	} else {
+		rows = append(rows, faint.Render("  [tab] to complete"))
+	}
+
+	return lg.NewStyle().
+		Border(lg.NormalBorder(), false, false, false, true).
+		BorderForeground(lg.Color("#555555")).
+		MarginLeft(2).
+		Render(strings.Join(rows, "\n"))
+}
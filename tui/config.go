@@ -19,11 +19,98 @@ type config struct {
 	//  - a file, which will have new items appended as new lines, or
 	//  - a directory, which will be written with YYYY-MM-DD.xit files for each day
 	writeto string
+
+	// snooze is the policy used by the `z` key to decide how long snoozed items
+	// stay hidden. See tuido.ParseSnoozePolicy for the accepted values.
+	//
+	// default value for snooze is "fibonacci" (1, 2, 3, 5, 8, ... days).
+	snooze string
+
+	// tagSnooze overrides the snooze policy for items carrying a given tag.
+	// It is configured by lines of the form `snooze#tagname=policy`.
+	tagSnooze map[string]string
+
+	// archive is the file that done items are archived to. YYYY, MM, and DD
+	// are replaced with the date that the item was completed.
+	//
+	// default value for archive is "~/.tuido/archive/YYYY-MM.xit".
+	archive string
+
+	// archiveAfter is the age (eg, 30d or 6M) at which done items are archived
+	// automatically on startup. Empty by default, meaning never.
+	archiveAfter string
+
+	// detail is the position of the detail pane shown alongside the item
+	// list: "right" or "bottom". The pane is toggled in app with `v`, and
+	// is shown at startup if detail is set. Empty by default.
+	detail string
+
+	// pomoWork, pomoBreak, and pomoLongBreak are the lengths of pomodoro
+	// focus periods and breaks, as go durations (eg, 25m). A long break
+	// replaces every pomoCycles-th short break.
+	//
+	// default values are 25m, 5m, 15m, and 4.
+	pomoWork      string
+	pomoBreak     string
+	pomoLongBreak string
+	pomoCycles    string
+
+	// notify is a comma separated list of where to send notifications of
+	// pomodoro phases ending and items coming due: any of bell, osc9,
+	// osc777, and dbus, or none.
+	//
+	// default value for notify is "bell".
+	notify string
+
+	// capacity is the work that fits in a day, compared against the
+	// #estimate tags of the items planned for today (eg, 6h).
+	//
+	// default value for capacity is "6h".
+	capacity string
+
+	// rollover is what happens to items planned for a previous day that
+	// are still unfinished at the first launch of a new one: "prompt"
+	// opens the planning screen to choose which to keep for today, and
+	// "auto" plans them all for today.
+	//
+	// default value for rollover is "prompt".
+	rollover string
+
+	// nagAfter is the number of items at which creating another is met
+	// with a challenge, and nagMax caps the challenge's level, which
+	// grows with each item past nagAfter.
+	//
+	// default values are 5 and 9.
+	nagAfter string
+	nagMax   string
+
+	// nagChallenge is the challenge to overcome: typing random "letters",
+	// typing the "count" of items, writing a reason to "justify" the new
+	// item, or waiting out a "cooldown".
+	//
+	// default value for nagChallenge is "letters".
+	nagChallenge string
+
+	// nagScope is which items are counted: those listed in the current
+	// "view", the pending items of the "project" (or @context) the list
+	// is narrowed to, or the "ongoing" items.
+	//
+	// default value for nagScope is "view".
+	nagScope string
 }
 
 func (cfg config) String() string {
-	return fmt.Sprintf("extensions=%s\nwriteto=%s\n",
-		strings.Join(cfg.extensions, ","), cfg.writeto)
+	ret := fmt.Sprintf("extensions=%s\nwriteto=%s\nsnooze=%s\narchive=%s\narchiveafter=%s\ndetail=%s\n",
+		strings.Join(cfg.extensions, ","), cfg.writeto, cfg.snooze, cfg.archive, cfg.archiveAfter, cfg.detail)
+	ret += fmt.Sprintf("pomowork=%s\npomobreak=%s\npomolongbreak=%s\npomocycles=%s\nnotify=%s\n",
+		cfg.pomoWork, cfg.pomoBreak, cfg.pomoLongBreak, cfg.pomoCycles, cfg.notify)
+	ret += fmt.Sprintf("capacity=%s\nrollover=%s\n", cfg.capacity, cfg.rollover)
+	ret += fmt.Sprintf("nagafter=%s\nnagmax=%s\nnagchallenge=%s\nnagscope=%s\n",
+		cfg.nagAfter, cfg.nagMax, cfg.nagChallenge, cfg.nagScope)
+	for tag, policy := range cfg.tagSnooze {
+		ret += fmt.Sprintf("snooze#%s=%s\n", tag, policy)
+	}
+	return ret
 }
 
 // runConfig is the initial, default values for the application configuration.
@@ -36,6 +123,24 @@ func (cfg config) String() string {
 var runConfig config = config{
 	extensions: []string{"xit", "md", "txt"},
 	writeto:    "~/.tuido",
+	snooze:     "fibonacci",
+	tagSnooze:  map[string]string{},
+	archive:    "~/.tuido/archive/YYYY-MM.xit",
+
+	pomoWork:      "25m",
+	pomoBreak:     "5m",
+	pomoLongBreak: "15m",
+	pomoCycles:    "4",
+
+	notify: "bell",
+
+	capacity: "6h",
+	rollover: "prompt",
+
+	nagAfter:     "5",
+	nagMax:       "9",
+	nagChallenge: "letters",
+	nagScope:     "view",
 }
 
 func adoptConfigSettings(location string) {
@@ -46,6 +151,60 @@ func adoptConfigSettings(location string) {
 		if config.writeto != "" {
 			runConfig.writeto = config.writeto
 		}
+		runConfig.adoptSettings(*config)
+	}
+}
+
+// adoptSettings overwrites cfg's settings with those that are set in other.
+// extensions and writeto are not included, as their callers combine them differently.
+func (cfg *config) adoptSettings(other config) {
+	if other.snooze != "" {
+		cfg.snooze = other.snooze
+	}
+	for tag, policy := range other.tagSnooze {
+		cfg.tagSnooze[tag] = policy
+	}
+	if other.archive != "" {
+		cfg.archive = other.archive
+	}
+	if other.archiveAfter != "" {
+		cfg.archiveAfter = other.archiveAfter
+	}
+	if other.detail != "" {
+		cfg.detail = other.detail
+	}
+	if other.pomoWork != "" {
+		cfg.pomoWork = other.pomoWork
+	}
+	if other.pomoBreak != "" {
+		cfg.pomoBreak = other.pomoBreak
+	}
+	if other.pomoLongBreak != "" {
+		cfg.pomoLongBreak = other.pomoLongBreak
+	}
+	if other.pomoCycles != "" {
+		cfg.pomoCycles = other.pomoCycles
+	}
+	if other.notify != "" {
+		cfg.notify = other.notify
+	}
+	if other.capacity != "" {
+		cfg.capacity = other.capacity
+	}
+	if other.rollover != "" {
+		cfg.rollover = other.rollover
+	}
+	if other.nagAfter != "" {
+		cfg.nagAfter = other.nagAfter
+	}
+	if other.nagMax != "" {
+		cfg.nagMax = other.nagMax
+	}
+	if other.nagChallenge != "" {
+		cfg.nagChallenge = other.nagChallenge
+	}
+	if other.nagScope != "" {
+		cfg.nagScope = other.nagScope
 	}
 }
 
@@ -67,7 +226,7 @@ func parseConfigIfExists(configPath string) *config {
 // This allows the .tuido file to be used as both configuration and as an
 // append target for new items authored in-tui.
 func parseConfig(file *os.File) config {
-	cfg := config{}
+	cfg := config{tagSnooze: map[string]string{}}
 
 	scanner := bufio.NewScanner(file)
 
@@ -84,6 +243,54 @@ func parseConfig(file *os.File) config {
 			if split[0] == "writeto" {
 				cfg.writeto = split[1]
 			}
+			if split[0] == "snooze" {
+				cfg.snooze = split[1]
+			}
+			if strings.HasPrefix(split[0], "snooze#") {
+				cfg.tagSnooze[strings.TrimPrefix(split[0], "snooze#")] = split[1]
+			}
+			if split[0] == "archive" {
+				cfg.archive = split[1]
+			}
+			if split[0] == "archiveafter" {
+				cfg.archiveAfter = split[1]
+			}
+			if split[0] == "detail" {
+				cfg.detail = split[1]
+			}
+			if split[0] == "pomowork" {
+				cfg.pomoWork = split[1]
+			}
+			if split[0] == "pomobreak" {
+				cfg.pomoBreak = split[1]
+			}
+			if split[0] == "pomolongbreak" {
+				cfg.pomoLongBreak = split[1]
+			}
+			if split[0] == "pomocycles" {
+				cfg.pomoCycles = split[1]
+			}
+			if split[0] == "notify" {
+				cfg.notify = split[1]
+			}
+			if split[0] == "capacity" {
+				cfg.capacity = split[1]
+			}
+			if split[0] == "rollover" {
+				cfg.rollover = split[1]
+			}
+			if split[0] == "nagafter" {
+				cfg.nagAfter = split[1]
+			}
+			if split[0] == "nagmax" {
+				cfg.nagMax = split[1]
+			}
+			if split[0] == "nagchallenge" {
+				cfg.nagChallenge = split[1]
+			}
+			if split[0] == "nagscope" {
+				cfg.nagScope = split[1]
+			}
 
 		} else {
 			// not a config line:
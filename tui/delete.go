@@ -0,0 +1,80 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+func (t *tui) setDeleteMode() {
+	if t.currentSelection() != nil {
+		t.mode = deleting
+	}
+}
+
+// updateDeleting asks for confirmation before deleting the current selection.
+func (t *tui) updateDeleting(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y", "enter":
+			t.deleteSelection()
+			t.mode = navigation
+		case "n", "N", "esc", "q":
+			t.mode = navigation
+		}
+	}
+	return nil
+}
+
+// deleteSelection removes the current selection from disk, remembering
+// it so that the deletion can be undone.
+func (t *tui) deleteSelection() {
+	item := t.currentSelection()
+	if item == nil {
+		return
+	}
+
+	t.stopClockOn(item)
+	deletion, err := item.Delete(t.items)
+	t.err = err
+	if err != nil {
+		return
+	}
+	t.deletions = append(t.deletions, deletion)
+
+	kept := []*tuido.Item{}
+	for _, i := range t.items {
+		if i != item {
+			kept = append(kept, i)
+		}
+	}
+	t.items = kept
+	t.populateRenderSelection()
+	t.setSelection(t.selection)
+}
+
+// undoDeletion restores the most recently deleted item.
+func (t *tui) undoDeletion() {
+	if len(t.deletions) == 0 {
+		t.notifs = append(t.notifs, "Nothing to undo")
+		return
+	}
+
+	last := t.deletions[len(t.deletions)-1]
+	t.err = last.Restore(t.items)
+	if t.err != nil {
+		return
+	}
+	t.deletions = t.deletions[:len(t.deletions)-1]
+
+	// return the item to its place in parse order
+	at := len(t.items)
+	for i, item := range t.items {
+		if item.File() == last.Item.File() && item.Line() > last.Item.Line() {
+			at = i
+			break
+		}
+	}
+	t.items = append(t.items[:at], append([]*tuido.Item{last.Item}, t.items[at:]...)...)
+	t.populateRenderSelection()
+	t.selectItem(last.Item)
+}
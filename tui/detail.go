@@ -0,0 +1,285 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// detailMinWidth is the narrowest terminal that fits the detail pane
+// beside the list. Narrower terminals show it below the list instead.
+const detailMinWidth = 100
+
+// detailPosition returns where the detail pane is shown: "right",
+// "bottom", or "" if it is hidden.
+func (t tui) detailPosition() string {
+	if !t.showDetail {
+		return ""
+	}
+	if t.config.detail == "bottom" || t.w < detailMinWidth {
+		return "bottom"
+	}
+	return "right"
+}
+
+// toggleDetail shows or hides the detail pane.
+func (t *tui) toggleDetail() {
+	t.showDetail = !t.showDetail
+}
+
+// listWithDetail renders the item list alongside the detail pane for
+// the current selection, in the given space.
+func (t *tui) listWithDetail(height, width int) string {
+	switch t.detailPosition() {
+	case "right":
+		listWidth := width * 55 / 100
+		list := t.renderVisibleListedItems(height, listWidth)
+		detail := t.detailView(height, width-listWidth-1)
+		return lg.JoinHorizontal(lg.Top, lg.NewStyle().Width(listWidth).Render(list), detail)
+	case "bottom":
+		listHeight := height / 2
+		list := t.renderVisibleListedItems(listHeight, width)
+		return lg.JoinVertical(lg.Left, list, t.detailView(height-listHeight, width))
+	}
+	return t.renderVisibleListedItems(height, width)
+}
+
+// detailView describes the current selection: its surrounding file
+// context, tags, dates, time tracking, and git history.
+func (t tui) detailView(height, width int) string {
+	border := lg.NewStyle().
+		Border(lg.RoundedBorder()).
+		BorderForeground(lg.Color("#555555")).
+		Padding(0, 1)
+	// lipgloss widths include padding, but not borders
+	boxWidth := max(1, width-2)
+	innerWidth := max(1, width-border.GetHorizontalFrameSize())
+	innerHeight := max(1, height-border.GetVerticalFrameSize())
+
+	item := t.currentSelection()
+	if item == nil {
+		return border.Width(boxWidth).Height(innerHeight).Render("no item selected")
+	}
+
+	faint := lg.NewStyle().Faint(true)
+	heading := lg.NewStyle().Bold(true)
+
+	sections := []string{faint.Render(displayPath(item.Location()))}
+
+	if tags := t.detailTags(*item); tags != "" {
+		sections = append(sections, heading.Render("tags"), tags)
+	}
+	if dates := detailDates(*item); dates != "" {
+		sections = append(sections, heading.Render("dates"), dates)
+	}
+	if tracking := detailTracking(*item); tracking != "" {
+		sections = append(sections, heading.Render("time"), tracking)
+	}
+	sections = append(sections, heading.Render("git"), t.blame(*item))
+
+	info := lg.JoinVertical(lg.Left, sections...)
+
+	// the file context fills what space remains
+	contextHeight := innerHeight - lg.Height(info) - 2
+	if contextHeight >= 3 {
+		context, _ := item.GetContext(contextHeight)
+		context = lg.NewStyle().MaxHeight(contextHeight).Render(context)
+		info = lg.JoinVertical(lg.Left, info, "", heading.Render("context"), context)
+	}
+
+	return border.
+		Width(boxWidth).
+		Height(innerHeight).
+		MaxHeight(height).
+		Render(lg.NewStyle().MaxWidth(innerWidth).MaxHeight(innerHeight).Render(info))
+}
+
+// detailTags lays out the item's tags as a table of names and values.
+func (t tui) detailTags(item tuido.Item) string {
+	tags := item.Tags()
+	nameWidth := 0
+	for _, tag := range tags {
+		nameWidth = max(nameWidth, len(tag.Name()))
+	}
+
+	rows := []string{}
+	for _, tag := range tags {
+		name := fmt.Sprintf("%-*s", nameWidth, tag.Name())
+		if style, ok := t.tagColors[tag.Name()]; ok {
+			name = style.Render(name)
+		}
+		rows = append(rows, "  "+name+"  "+tag.Value())
+	}
+	for _, p := range item.Projects() {
+		rows = append(rows, "  +"+p)
+	}
+	for _, c := range item.Contexts() {
+		rows = append(rows, "  @"+c)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func detailDates(item tuido.Item) string {
+	rows := []string{}
+	add := func(name string, d *time.Time) {
+		if d != nil {
+			rows = append(rows, fmt.Sprintf("  %-9s %s (%s)", name, formatDate(*d), relative(*d)))
+		}
+	}
+	add("due", item.Due())
+	add("active", item.ActiveDate())
+	add("created", item.Created())
+	add("completed", item.Completed())
+
+	if n := item.SnoozeCount(); n > 0 {
+		rows = append(rows, fmt.Sprintf("  snoozed %d time(s)", n))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func detailTracking(item tuido.Item) string {
+	estimate, spent := item.Estimate(), item.Spent()
+	if estimate == nil && spent == 0 {
+		return ""
+	}
+
+	rows := []string{"  estimate  -", "  spent     " + tuido.FormatEffort(spent)}
+	if estimate != nil {
+		rows[0] = "  estimate  " + tuido.FormatEffort(*estimate)
+		if *estimate > 0 {
+			rows[1] += fmt.Sprintf(" (%.0f%%)", 100*float64(spent)/float64(*estimate))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// formatDate formats d for display, omitting the time for midnight.
+func formatDate(d time.Time) string {
+	if d.Hour() == 0 && d.Minute() == 0 {
+		return d.Format("Mon Jan 2 2006")
+	}
+	return d.Format("Mon Jan 2 2006 15:04")
+}
+
+// relative describes d relative to now, in whole days or hours.
+func relative(d time.Time) string {
+	diff := time.Until(d)
+	suffix := "from now"
+	if diff < 0 {
+		diff = -diff
+		suffix = "ago"
+	}
+
+	switch {
+	case diff < time.Hour:
+		return "now"
+	case diff < 24*time.Hour:
+		return fmt.Sprintf("%dh %s", int(diff.Hours()), suffix)
+	}
+	return fmt.Sprintf("%dd %s", int(diff.Hours()/24), suffix)
+}
+
+// fileBlame caches the git blame descriptions of a file's lines, for as
+// long as the file is unchanged.
+type fileBlame struct {
+	modTime time.Time
+	// lines are keyed by line number. An empty description is a blame
+	// still running.
+	lines map[int]string
+}
+
+// blameMsg carries the git blame description of line of file, as it was
+// at modTime.
+type blameMsg struct {
+	file    string
+	line    int
+	modTime time.Time
+	blamed  string
+}
+
+// blame returns the git author and date of the item's line, once
+// blameSelection has looked them up.
+func (t tui) blame(item tuido.Item) string {
+	if cached, ok := t.blames[item.File()]; ok && cached.lines[item.Line()] != "" {
+		return cached.lines[item.Line()]
+	}
+	return "  ..."
+}
+
+// blameSelection runs git blame in the background for the selected
+// item's line, if the detail pane is shown and the line's blame is not
+// already cached. A file's cached blames are dropped when it changes.
+func (t *tui) blameSelection() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil || t.detailPosition() == "" {
+		return nil
+	}
+	file, line := item.File(), item.Line()
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil
+	}
+
+	cached, ok := t.blames[file]
+	if !ok || !cached.modTime.Equal(info.ModTime()) {
+		cached = &fileBlame{modTime: info.ModTime(), lines: map[int]string{}}
+		t.blames[file] = cached
+	}
+	if _, ok := cached.lines[line]; ok {
+		return nil
+	}
+	cached.lines[line] = ""
+
+	modTime := info.ModTime()
+	return func() tea.Msg {
+		return blameMsg{file: file, line: line, modTime: modTime, blamed: gitBlame(file, line)}
+	}
+}
+
+// storeBlame caches a finished blame, unless its file changed meanwhile.
+func (t *tui) storeBlame(msg blameMsg) {
+	if cached, ok := t.blames[msg.file]; ok && cached.modTime.Equal(msg.modTime) {
+		cached.lines[msg.line] = msg.blamed
+	}
+}
+
+// gitBlame describes who last changed line of file, and when.
+func gitBlame(file string, line int) string {
+	cmd := exec.Command("git", "blame", "--porcelain",
+		"-L", fmt.Sprintf("%d,%d", line, line), "--", filepath.Base(file))
+	cmd.Dir = filepath.Dir(file)
+	out, err := cmd.Output()
+	if err != nil {
+		return "  not tracked by git"
+	}
+
+	var author string
+	var when time.Time
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		field, value, _ := strings.Cut(scanner.Text(), " ")
+		switch field {
+		case "author":
+			author = value
+		case "author-time":
+			if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+				when = time.Unix(secs, 0)
+			}
+		}
+	}
+
+	if author == "Not Committed Yet" {
+		return "  not committed yet"
+	}
+	return fmt.Sprintf("  %s\n  %s (%s)", author, formatDate(when), relative(when))
+}
@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// descriptionEditor edits the lines of an item's description, below
+// the item editor for its first line.
+type descriptionEditor struct {
+	lines []textinput.Model
+	// focus is the index of the line being edited, or -1 while the
+	// item's first line is being edited
+	focus int
+	// original is the description when editing began
+	original []string
+}
+
+func newDescriptionLine(txt string) textinput.Model {
+	line := textinput.New()
+	line.Prompt = ""
+	line.Placeholder = "description"
+	line.SetValue(txt)
+	return line
+}
+
+func (t *tui) setEditMode() tea.Cmd {
+	if t.currentSelection() != nil {
+		t.mode = edit
+		t.itemEditor.SetValue(t.currentSelection().Text())
+		t.itemEditor.CursorEnd()
+		t.itemEditor.Focus()
+		t.completer = newTagCompleter(t.items)
+
+		description := t.currentSelection().Description()
+		t.descEditor = descriptionEditor{focus: -1, original: description}
+		for _, l := range description {
+			t.descEditor.lines = append(t.descEditor.lines, newDescriptionLine(l))
+		}
+	}
+	return nil
+}
+
+// focusEditorLine moves the editing cursor to the given line: -1 for
+// the item's first line, or else a description line.
+func (t *tui) focusEditorLine(line int) {
+	d := &t.descEditor
+	line = max(-1, min(line, len(d.lines)-1))
+
+	t.itemEditor.Blur()
+	for i := range d.lines {
+		d.lines[i].Blur()
+	}
+
+	d.focus = line
+	if line == -1 {
+		t.itemEditor.Focus()
+	} else {
+		d.lines[line].Focus()
+		d.lines[line].CursorEnd()
+	}
+	t.completer.suggestions = nil
+}
+
+// updateEdit handles keypresses in the item editor.
+func (t *tui) updateEdit(msg tea.Msg) tea.Cmd {
+	d := &t.descEditor
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if len(t.completer.suggestions) > 0 {
+			switch msg.String() {
+			case "esc":
+				t.completer.suggestions = nil // dismiss completions
+				return nil
+			case "tab":
+				txt, cursor := t.completer.complete(t.itemEditor.Value(), t.itemEditor.Cursor())
+				t.itemEditor.SetValue(txt)
+				t.itemEditor.SetCursor(cursor)
+				t.completer.update(txt, cursor)
+				return nil
+			case "up":
+				t.completer.move(-1)
+				return nil
+			case "down":
+				t.completer.move(1)
+				return nil
+			}
+		}
+
+		switch msg.String() {
+		case "esc":
+			t.mode = navigation // abandon changes
+			t.override = nil
+			return nil
+		case "enter":
+			t.saveEdit()
+			return nil
+		case "ctrl+j", "alt+enter":
+			// add a description line below the current line
+			at := d.focus + 1
+			d.lines = append(d.lines[:at], append([]textinput.Model{newDescriptionLine("")}, d.lines[at:]...)...)
+			t.focusEditorLine(at)
+			return nil
+		case "up":
+			t.focusEditorLine(d.focus - 1)
+			return nil
+		case "down":
+			t.focusEditorLine(d.focus + 1)
+			return nil
+		case "backspace":
+			// backspacing out of an empty description line removes it
+			if d.focus >= 0 && d.lines[d.focus].Value() == "" {
+				d.lines = append(d.lines[:d.focus], d.lines[d.focus+1:]...)
+				t.focusEditorLine(d.focus - 1)
+				return nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if d.focus >= 0 {
+		d.lines[d.focus], cmd = d.lines[d.focus].Update(msg)
+		return cmd
+	}
+
+	t.itemEditor, cmd = t.itemEditor.Update(msg)
+	if _, ok := msg.(tea.KeyMsg); ok {
+		t.completer.update(t.itemEditor.Value(), t.itemEditor.Cursor())
+	}
+	return cmd
+}
+
+// saveEdit writes the edited item text and description to disk.
+func (t *tui) saveEdit() {
+	txt := t.itemEditor.Value()
+	if txt == "" {
+		return
+	}
+
+	item := t.currentSelection()
+	t.err = item.SetText(tuido.ExpandTimeEntries(txt))
+	if t.err != nil {
+		return
+	}
+
+	description := []string{}
+	for _, l := range t.descEditor.lines {
+		if l.Value() != "" {
+			description = append(description, l.Value())
+		}
+	}
+	if !reflect.DeepEqual(description, t.descEditor.original) &&
+		!(len(description) == 0 && len(t.descEditor.original) == 0) {
+		t.err = item.SetDescription(description, t.items)
+	}
+	t.mode = navigation
+
+	if o := t.override; o != nil {
+		t.override = nil
+		o.item, o.text = item.Location(), item.Text()
+		if err := appendOverride(overridesPath, *o); err != nil && t.err == nil {
+			t.err = fmt.Errorf("error logging nag override: %w", err)
+		}
+	}
+}
+
+// descriptionEditorView renders the description lines being edited,
+// indented beneath the item editor.
+func (t tui) descriptionEditorView() string {
+	rows := []string{}
+	for _, l := range t.descEditor.lines {
+		rows = append(rows, "      "+l.View())
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	return lg.JoinVertical(lg.Left, rows...)
+}
+
+// descriptionView renders an item's description, word-wrapped to width.
+func descriptionView(description []string, width int) string {
+	return lg.NewStyle().
+		Faint(true).
+		MarginLeft(6).
+		Width(max(1, width-6)).
+		Render(lg.JoinVertical(lg.Left, description...))
+}
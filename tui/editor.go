@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// openInEditor requests that the current selection be opened in the
+// user's editor. The program quits, and Run launches the editor before
+// restarting it.
+func (t *tui) openInEditor() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil {
+		return nil
+	}
+	req := editorRequest{file: item.File(), line: item.Line(), text: item.Text()}
+
+	// from peek, open at the line being looked at
+	if t.mode == peek {
+		req = editorRequest{file: t.peek.item.File(), line: t.peek.cursor + 1}
+		if item := t.peek.itemAt(t.peek.cursor); item != nil {
+			req.text = item.Text()
+		}
+	}
+
+	t.editing = &req
+	return tea.Quit
+}
+
+// editorRequest is an item location to open in the user's editor.
+type editorRequest struct {
+	file string
+	line int
+	// text is the item's text, used to find it again if its line moves
+	text string
+}
+
+// editor returns the user's preferred editor command.
+func editor() (string, error) {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("set $VISUAL or $EDITOR to open items in an editor")
+}
+
+// editorArgs returns the arguments that open file at line in the
+// named editor.
+func editorArgs(name, file string, line int) []string {
+	at := fmt.Sprintf("%s:%d", file, line)
+
+	switch strings.TrimSuffix(filepath.Base(name), ".exe") {
+	case "code", "code-insiders", "codium":
+		return []string{"--goto", at}
+	case "hx", "helix", "subl", "micro":
+		return []string{at}
+	}
+	// vi, vim, nvim, emacs, emacsclient, nano, kak, etc.
+	return []string{fmt.Sprintf("+%d", line), file}
+}
+
+// runEditor opens the requested file in the user's editor and waits
+// for it to exit.
+func runEditor(req editorRequest) error {
+	e, err := editor()
+	if err != nil {
+		return err
+	}
+
+	command := strings.Fields(e)
+	args := append(command[1:], editorArgs(command[0], req.file, req.line)...)
+
+	cmd := exec.Command(command[0], args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// reloadFile re-parses file after it was changed outside of tuido,
+// replacing its items, and selects the item best matching req.
+func (t *tui) reloadFile(req editorRequest) {
+	fresh := []*tuido.Item{}
+	if _, err := os.Stat(req.file); err == nil {
+		fresh = getItems(req.file)
+	}
+
+	kept := []*tuido.Item{}
+	for _, item := range t.items {
+		if !item.InFile(req.file) {
+			kept = append(kept, item)
+		}
+	}
+	t.items = append(kept, fresh...)
+
+	// line numbers in the file may have changed, so its deletions can no longer be undone
+	deletions := []*tuido.Deletion{}
+	for _, d := range t.deletions {
+		if !d.Item.InFile(req.file) {
+			deletions = append(deletions, d)
+		}
+	}
+	t.deletions = deletions
+
+	// the clock follows its item into the re-parsed file
+	if c := t.clock; c != nil && c.item.InFile(req.file) {
+		if c.item = relocate(fresh, c.item.Text(), c.item.Line()); c.item == nil {
+			t.clock = nil
+			t.notifs = append(t.notifs, fmt.Sprintf(
+				"The clock's item was removed in the editor. Its time since %s was not recorded.",
+				c.start.Format("15:04")))
+		}
+	}
+
+	sortItems(t.items)
+	t.tagColors = populateTagColorStyles(t.items)
+	t.populateRenderSelection()
+
+	t.selectItem(relocate(fresh, req.text, req.line))
+
+	// the peek screen's lines and items are of the file as it was, so it
+	// is rebuilt on the re-parsed file, at the line that was edited
+	if t.mode == peek {
+		t.repeek(fresh, req)
+	}
+}
+
+// repeek reopens the peek screen on the re-parsed items of req's file,
+// or returns to navigation if it can't be read or has no items left.
+func (t *tui) repeek(fresh []*tuido.Item, req editorRequest) {
+	t.mode = navigation
+
+	if item := relocate(fresh, req.text, req.line); item != nil {
+		t.peekAt(item, item.Line()-1)
+		return
+	}
+
+	// the edited item is gone: peek at whichever is now nearest its line
+	var nearest *tuido.Item
+	for _, i := range fresh {
+		if nearest == nil || abs(i.Line()-req.line) < abs(nearest.Line()-req.line) {
+			nearest = i
+		}
+	}
+	if nearest != nil {
+		t.peekAt(nearest, req.line-1)
+	}
+}
+
+// relocate finds an item in freshly parsed items: preferring the item
+// with the same text nearest its old line, or else whichever item is
+// now on that line.
+func relocate(fresh []*tuido.Item, text string, line int) *tuido.Item {
+	var found *tuido.Item
+	for _, item := range fresh {
+		if item.Text() == text &&
+			(found == nil || abs(item.Line()-line) < abs(found.Line()-line)) {
+			found = item
+		}
+	}
+	for _, item := range fresh {
+		if found == nil && item.Line() == line {
+			found = item
+		}
+	}
+	return found
+}
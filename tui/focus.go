@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// focusScreen is a distraction-free view of a single item, timed by the
+// stopwatch, with its subtasks as a checklist and a scratch pad of notes.
+type focusScreen struct {
+	item     *tuido.Item
+	subtasks []*tuido.Item
+	selected int
+
+	// notes are written during the session, and appended to the item's
+	// description when it ends
+	notes []string
+	note  textinput.Model
+
+	// clocked is set if the session started the stopwatch, which then
+	// stops with it
+	clocked bool
+}
+
+func (t *tui) setFocusMode() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil {
+		return nil
+	}
+
+	note := textinput.New()
+	note.Prompt = "> "
+	note.Placeholder = "jot down a note"
+
+	t.mode = focus
+	t.focus = focusScreen{
+		item:     item,
+		subtasks: item.Subtasks(t.items),
+		note:     note,
+	}
+
+	if t.clock == nil || t.clock.item != item {
+		t.focus.clocked = true
+		return t.toggleClock()
+	}
+	return nil
+}
+
+// endFocus saves the session's notes and stops its clock.
+func (t *tui) endFocus() {
+	f := &t.focus
+
+	if note := strings.TrimSpace(f.note.Value()); note != "" {
+		f.notes = append(f.notes, note)
+	}
+	if len(f.notes) > 0 {
+		t.err = f.item.SetDescription(append(f.item.Description(), f.notes...), t.items)
+	}
+	if f.clocked && t.clock != nil && t.clock.item == f.item {
+		t.stopClock(time.Now())
+	}
+
+	t.mode = navigation
+	t.repopulateKeepingSelection()
+	t.selectItem(f.item)
+}
+
+func (t *tui) updateFocus(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	f := &t.focus
+
+	if f.note.Focused() {
+		switch key.String() {
+		case "enter":
+			if note := strings.TrimSpace(f.note.Value()); note != "" {
+				f.notes = append(f.notes, note)
+			}
+			f.note.SetValue("")
+		case "esc", "tab":
+			f.note.Blur()
+		default:
+			var cmd tea.Cmd
+			f.note, cmd = f.note.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch key.String() {
+	case "up", "k":
+		f.selected = max(0, f.selected-1)
+	case "down", "j":
+		f.selected = max(0, min(len(f.subtasks)-1, f.selected+1))
+	case " ", "enter":
+		if f.selected < len(f.subtasks) {
+			sub := f.subtasks[f.selected]
+			if sub.Satus() == tuido.Checked {
+				t.err = sub.SetStatus(tuido.Open)
+			} else {
+				t.err = sub.SetStatus(tuido.Checked)
+			}
+		}
+	case "n", "tab":
+		f.note.Focus()
+		return textinput.Blink
+	case "a":
+		t.err = f.item.SetStatus(tuido.Ongoing)
+	case "x":
+		// the session ends with the item done, and the clock stopped on
+		// it, whether or not the session started it
+		t.err = nil
+		t.endFocus()
+		if t.err == nil {
+			t.err = f.item.SetStatus(tuido.Checked)
+		}
+		t.stopClockOn(f.item)
+		t.repopulateKeepingSelection()
+	case "esc", "q":
+		t.endFocus()
+	}
+	return nil
+}
+
+func (t tui) focusView() string {
+	f := t.focus
+	width := min(t.w-4, 80)
+	faint := lg.NewStyle().Faint(true)
+	heading := lg.NewStyle().Bold(true)
+
+	clock := ""
+	if t.clock != nil && t.clock.item == f.item {
+		clock = clockStyle.Render("● " + formatElapsed(time.Since(t.clock.start)))
+		if estimate := f.item.Estimate(); estimate != nil {
+			clock += faint.Render(fmt.Sprintf("  of %s estimated, %s spent before",
+				tuido.FormatEffort(*estimate), tuido.FormatEffort(f.item.Spent())))
+		}
+	}
+
+	rows := []string{
+		faint.Render("focus") + "  " + clock,
+		"",
+		lg.NewStyle().Bold(true).Width(width).Render(f.item.Satus().String() + " " + f.item.Text()),
+	}
+	if description := f.item.Description(); len(description) > 0 {
+		rows = append(rows, descriptionView(description, width))
+	}
+
+	if len(f.subtasks) > 0 {
+		done := 0
+		checklist := []string{}
+		for i, sub := range f.subtasks {
+			if sub.Satus() == tuido.Checked {
+				done++
+			}
+			row := sub.Satus().String() + " " + sub.Text()
+			if i == f.selected && !f.note.Focused() {
+				checklist = append(checklist, "> "+lg.NewStyle().Bold(true).Render(row))
+			} else {
+				checklist = append(checklist, "  "+row)
+			}
+		}
+		rows = append(rows, "", heading.Render(fmt.Sprintf("subtasks %d/%d", done, len(f.subtasks))))
+		rows = append(rows, checklist...)
+	}
+
+	rows = append(rows, "", heading.Render("notes"))
+	for _, note := range f.notes {
+		rows = append(rows, "  "+note)
+	}
+	if f.note.Focused() {
+		rows = append(rows, f.note.View())
+	}
+
+	keys := "[space]: check subtask   n: write a note   a: mark ongoing   x: mark done and finish   [esc]: finish"
+	if f.note.Focused() {
+		keys = "[enter]: add note   [esc]: back to the checklist"
+	}
+	rows = append(rows, "", faint.Render("notes are added to the item's description when the session ends"), faint.Render(keys))
+
+	if t.err != nil {
+		rows = append(rows, lg.NewStyle().Bold(true).Foreground(lg.Color("#ff2222")).Render(t.err.Error()))
+	}
+
+	return lg.NewStyle().Margin(2, 4).Render(lg.JoinVertical(lg.Left, rows...))
+}
@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// inboxAction is a processing step that prompts for input.
+type inboxAction string
+
+const (
+	tagAction      inboxAction = "tags"
+	estimateAction inboxAction = "estimate"
+	scheduleAction inboxAction = "due"
+	projectAction  inboxAction = "project"
+)
+
+// inboxScreen presents unprocessed items one at a time for processing.
+type inboxScreen struct {
+	queue []*tuido.Item
+	index int
+
+	// action is the action awaiting input from editor, if any.
+	action inboxAction
+	editor textinput.Model
+}
+
+// inInbox reports whether item is an unprocessed capture: a pending item
+// in the writeto location with no #tags, +projects, or @contexts.
+func (t *tui) inInbox(item *tuido.Item) bool {
+	if item.Satus() != tuido.Open && item.Satus() != tuido.Ongoing {
+		return false
+	}
+	if len(item.Tags()) != 0 || len(item.Projects()) != 0 || len(item.Contexts()) != 0 {
+		return false
+	}
+
+	writeto, err := filepath.Abs(t.config.writeto)
+	if err != nil {
+		return false
+	}
+	file, err := filepath.Abs(item.File())
+	if err != nil {
+		return false
+	}
+
+	return file == writeto || filepath.Dir(file) == writeto
+}
+
+func (t *tui) inboxItems() []*tuido.Item {
+	inbox := []*tuido.Item{}
+	for _, item := range t.items {
+		if t.inInbox(item) {
+			inbox = append(inbox, item)
+		}
+	}
+	return inbox
+}
+
+// projectsDir is the directory that project files are kept in: the
+// writeto directory, or the directory containing the writeto file.
+func (t *tui) projectsDir() string {
+	if info, err := os.Stat(t.config.writeto); err == nil && info.IsDir() {
+		return t.config.writeto
+	}
+	return filepath.Dir(t.config.writeto)
+}
+
+func (t *tui) setInboxMode() {
+	editor := textinput.New()
+	t.inbox = inboxScreen{
+		queue:  t.inboxItems(),
+		editor: editor,
+	}
+
+	if len(t.inbox.queue) == 0 {
+		t.notifs = append(t.notifs, "Inbox is empty")
+		return
+	}
+	t.mode = processing
+}
+
+func (t *tui) nextInboxItem() {
+	t.inbox.index++
+	if t.inbox.index >= len(t.inbox.queue) {
+		if remaining := len(t.inboxItems()); remaining > 0 {
+			t.notifs = append(t.notifs, fmt.Sprintf("%d item(s) left in the inbox", remaining))
+		}
+		t.mode = navigation
+	}
+}
+
+func (t *tui) promptInbox(action inboxAction, placeholder string) {
+	t.inbox.action = action
+	t.inbox.editor.Prompt = string(action) + ": "
+	t.inbox.editor.Placeholder = placeholder
+	t.inbox.editor.SetValue("")
+	t.inbox.editor.Focus()
+}
+
+// updateInbox applies processing actions to the current inbox item.
+func (t *tui) updateInbox(msg tea.Msg) tea.Cmd {
+	item := t.inbox.queue[t.inbox.index]
+
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	if t.inbox.action != "" {
+		switch key.String() {
+		case "esc":
+			t.inbox.action = ""
+		case "enter":
+			t.err = t.applyInboxAction(item, t.inbox.action, strings.TrimSpace(t.inbox.editor.Value()))
+			t.inbox.action = ""
+			if t.err == nil {
+				t.nextInboxItem()
+			}
+		default:
+			var cmd tea.Cmd
+			t.inbox.editor, cmd = t.inbox.editor.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	t.err = nil
+	switch key.String() {
+	case "esc", "q":
+		t.mode = navigation
+	case "t":
+		t.promptInbox(tagAction, "#tag +project @context")
+	case "e":
+		t.promptInbox(estimateAction, "25m, 2h, 1d")
+	case "s":
+		t.promptInbox(scheduleAction, "friday, next week, 2026-11-03")
+	case "m":
+		t.promptInbox(projectAction, "project name")
+	case "d":
+		t.err = item.SetStatus(tuido.Obsolete)
+		if t.err == nil {
+			t.nextInboxItem()
+		}
+	case " ", "right", "l":
+		t.nextInboxItem()
+	}
+	return nil
+}
+
+func (t *tui) applyInboxAction(item *tuido.Item, action inboxAction, input string) error {
+	if input == "" {
+		return fmt.Errorf("no %s given", action)
+	}
+
+	switch action {
+	case tagAction:
+		return item.SetText(item.Text() + " " + input)
+	case estimateAction:
+		return item.SetTag("estimate", strings.TrimPrefix(input, "e"))
+	case scheduleAction:
+		return item.SetDate("due", input)
+	case projectAction:
+		name := strings.TrimPrefix(input, "+")
+		t.creditClockOn(item)
+		err := item.MoveTo(filepath.Join(t.projectsDir(), name+".xit"), t.items)
+		if err != nil {
+			return err
+		}
+		for _, p := range item.Projects() {
+			if p == name {
+				return nil
+			}
+		}
+		return item.SetText(item.Text() + " +" + name)
+	}
+	return nil
+}
+
+func (t tui) inboxView() string {
+	in := t.inbox
+	item := in.queue[in.index]
+
+	title := lg.NewStyle().Bold(true).Render(
+		fmt.Sprintf("Inbox - item %d of %d", in.index+1, len(in.queue)))
+
+	body := lg.JoinVertical(lg.Left,
+		t.renderTuido(*item, t.w-6),
+		lg.NewStyle().Faint(true).Render(item.Location()),
+	)
+
+	footer := ""
+	if in.action != "" {
+		footer = in.editor.View() + "\n" +
+			lg.NewStyle().Faint(true).Render("[enter]: apply   [esc]: cancel")
+	} else {
+		keys := []string{"t: tag", "e: estimate", "s: schedule (due)", "m: move to project file",
+			"d: discard (obsolete)", "[space]: skip", "esc: stop processing"}
+		footer = lg.NewStyle().Faint(true).Render(strings.Join(keys, "   "))
+	}
+
+	if t.err != nil {
+		footer = lg.JoinVertical(lg.Left, footer,
+			lg.NewStyle().Bold(true).Foreground(lg.Color("#ff2222")).Render(t.err.Error()))
+	}
+
+	return lg.NewStyle().Margin(1, 2).Render(lg.JoinVertical(lg.Left, title, "", body, "", footer))
+}
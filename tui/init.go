@@ -8,6 +8,11 @@ import (
 	"time"
 )
 
+// tuidoDir is the application's own directory, $HOME/.tuido. It is
+// the default writeto location, and holds persisted appState and the
+// pomodoro session log.
+var tuidoDir string
+
 func init() {
 	rand.Seed(time.Now().Unix()) // a fresh set of tag colors on each run. Spice of life.
 
@@ -15,11 +20,24 @@ func init() {
 	if err != nil {
 		fmt.Printf("error getting user home dir: %s", err)
 	}
-	tuidoDir := filepath.Join(home, ".tuido")
+	tuidoDir = filepath.Join(home, ".tuido")
 	runConfig.writeto = tuidoDir
+	runConfig.archive = filepath.Join(tuidoDir, "archive", "YYYY-MM.xit")
+	statePath = filepath.Join(tuidoDir, "tuido.state")
+	sessionsPath = filepath.Join(tuidoDir, "sessions.log")
+	overridesPath = filepath.Join(tuidoDir, "overrides.log")
 
 	loadFromDefaultConfigLocation()
 
+	// make sure the app directory exists, even if writeto is elsewhere
+	if _, err = os.Stat(tuidoDir); err != nil {
+		err = os.Mkdir(tuidoDir, 0777)
+		if err != nil {
+			fmt.Printf("error creating appDirectory %s': %v\n",
+				tuidoDir, err)
+		}
+	}
+
 	// make sure the write target exists
 	_, err = os.Open(runConfig.writeto)
 	if err != nil {
@@ -48,5 +66,6 @@ func loadFromDefaultConfigLocation() {
 		if cfg.writeto != "" {
 			runConfig.writeto = cfg.writeto
 		}
+		runConfig.adoptSettings(*cfg)
 	}
 }
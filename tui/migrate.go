@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MigrateCommand runs `tuido migrate`, which rewrites #spent tags in
+// the minutes format of older versions of tuido (#spent=37.50) as
+// durations (#spent=37m30s), and returns the process exit code.
+func MigrateCommand(args []string) int {
+	flags := flag.NewFlagSet("tuido migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "list the items that would be rewritten, without changing them")
+	includeArchives := flags.Bool("archives", false, "include archived items")
+	flags.Parse(args)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	adoptConfigSettings(filepath.Join(wd, ".tuido"))
+
+	_, items := scan(wd, *includeArchives)
+
+	count, failed := 0, 0
+	for _, item := range items {
+		if !item.LegacySpent() {
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("%s: %s\n", item.Location(), item.Text())
+			count++
+			continue
+		}
+
+		before := item.Text()
+		if _, err := item.MigrateSpent(); err != nil {
+			fmt.Printf("%s: %s\n", item.Location(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s: %s\n    -> %s\n", item.Location(), before, item.Text())
+		count++
+	}
+
+	if *dryRun {
+		fmt.Printf("%d item(s) to migrate\n", count)
+	} else {
+		fmt.Printf("migrated %d item(s)\n", count)
+	}
+	if failed > 0 {
+		fmt.Printf("%d item(s) could not be migrated\n", failed)
+		return 1
+	}
+	return 0
+}
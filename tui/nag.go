@@ -1,32 +1,149 @@
 package tui
 
 import (
+	"fmt"
 	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
 )
 
-func NewNag(prompt string, size int, exit mode) nagScreen {
-	// this is annoying enough
-	if size > 9 {
-		size = 9
+// nagChallenge is the task that stands between the user and a new item
+// once the nag policy's threshold is passed.
+type nagChallenge string
+
+const (
+	// lettersChallenge is typing out random letters, fib(level) of them
+	lettersChallenge nagChallenge = "letters"
+	// countChallenge is typing the number of counted items, which is not shown
+	countChallenge nagChallenge = "count"
+	// justifyChallenge is writing a reason for the new item, of 3 words
+	// per level, which is added to its description
+	justifyChallenge nagChallenge = "justify"
+	// cooldownChallenge is waiting fib(level) * 5 seconds
+	cooldownChallenge nagChallenge = "cooldown"
+)
+
+var nagChallenges = []nagChallenge{lettersChallenge, countChallenge, justifyChallenge, cooldownChallenge}
+
+// nagScope is which items are counted against the nag policy's threshold.
+type nagScope string
+
+const (
+	// viewNagScope counts the items listed in the current view
+	viewNagScope nagScope = "view"
+	// projectNagScope counts the pending items in the +project or @context
+	// that the list is narrowed to, or else in the inbox
+	projectNagScope nagScope = "project"
+	// ongoingNagScope counts the ongoing items, wherever they are
+	ongoingNagScope nagScope = "ongoing"
+)
+
+var nagScopes = []nagScope{viewNagScope, projectNagScope, ongoingNagScope}
+
+// nagPolicy decides when creating a new item is met with a challenge,
+// to deter piling more onto an already full plate.
+type nagPolicy struct {
+	// after is the number of counted items at which nagging begins
+	after int
+	// max caps the level of the challenge, which grows by one with
+	// each counted item past the threshold
+	max       int
+	challenge nagChallenge
+	scope     nagScope
+}
+
+var defaultNagPolicy = nagPolicy{after: 5, max: 9, challenge: lettersChallenge, scope: viewNagScope}
+
+// parseNagPolicy reads the nag settings of cfg. On error, the default
+// policy is returned.
+func parseNagPolicy(cfg config) (nagPolicy, error) {
+	p := defaultNagPolicy
+	var err error
+
+	if p.after, err = strconv.Atoi(cfg.nagAfter); err != nil || p.after < 1 {
+		return defaultNagPolicy, fmt.Errorf("invalid nagafter config: %s", cfg.nagAfter)
+	}
+	if p.max, err = strconv.Atoi(cfg.nagMax); err != nil || p.max < 1 {
+		return defaultNagPolicy, fmt.Errorf("invalid nagmax config: %s", cfg.nagMax)
 	}
 
-	nagLength := fib(size)
-	nagText := ""
+	p.challenge = ""
+	for _, c := range nagChallenges {
+		if string(c) == cfg.nagChallenge {
+			p.challenge = c
+		}
+	}
+	if p.challenge == "" {
+		return defaultNagPolicy, fmt.Errorf("unknown nagchallenge config: %s", cfg.nagChallenge)
+	}
 
-	for i := 0; i < nagLength; i++ {
-		nagText += string(rune('a' + rand.Intn(26)))
+	p.scope = ""
+	for _, s := range nagScopes {
+		if string(s) == cfg.nagScope {
+			p.scope = s
+		}
+	}
+	if p.scope == "" {
+		return defaultNagPolicy, fmt.Errorf("unknown nagscope config: %s", cfg.nagScope)
 	}
 
-	return nagScreen{prompt, nagText, exit}
+	return p, nil
+}
+
+// level returns the strength of the challenge to meet with count items
+// counted, or 0 if there is to be no challenge.
+func (p nagPolicy) level(count int) int {
+	if count < p.after {
+		return 0
+	}
+	return min(count-p.after+1, p.max)
+}
+
+func NewNag(prompt string, challenge nagChallenge, count, level int) nagScreen {
+	n := nagScreen{prompt: prompt, challenge: challenge, count: count, level: level}
+
+	switch challenge {
+	case lettersChallenge:
+		for i := 0; i < fib(level); i++ {
+			n.nagText += string(rune('a' + rand.Intn(26)))
+		}
+	case countChallenge, justifyChallenge:
+		n.answer = textinput.New()
+		n.answer.Prompt = "> "
+		n.answer.Focus()
+	case cooldownChallenge:
+		n.until = time.Now().Add(time.Duration(fib(level)) * 5 * time.Second)
+	}
+
+	return n
 }
 
 type nagScreen struct {
-	prompt  string
+	prompt    string
+	challenge nagChallenge
+	count     int
+	level     int
+
+	// nagText is what remains to be typed of a letters challenge
 	nagText string
-	exit    mode
+	// answer is the typed count or justification
+	answer textinput.Model
+	// hint explains a rejected answer
+	hint string
+	// until is the end of a cooldown
+	until time.Time
+}
+
+// words is the length of justification required at the nag's level.
+func (n *nagScreen) words() int {
+	return 3 * n.level
 }
 
 func (n *nagScreen) View() string {
@@ -34,34 +151,187 @@ func (n *nagScreen) View() string {
 	s := lg.NewStyle().Margin(2)
 
 	prompt := s.Render(n.prompt)
-	str := s.Render("type \"" + n.nagText + "\" to continue.")
+	var str string
+	switch n.challenge {
+	case lettersChallenge:
+		str = s.Render("type \"" + n.nagText + "\" to continue.")
+	case countChallenge:
+		str = s.Render("How many are there? Count them, and type the number to continue.\n\n" + n.answer.View())
+	case justifyChallenge:
+		str = s.Render(fmt.Sprintf("Why does this need doing now? Write at least %d words to continue.\nYour reason is added to the new item's description.\n\n", n.words()) +
+			n.answer.View())
+	case cooldownChallenge:
+		if remaining := time.Until(n.until); remaining > 0 {
+			str = s.Render("Take a breath. You can add the item in " + formatClock(remaining) + ".")
+		} else {
+			str = s.Render("[enter] to add the item.")
+		}
+	}
+	if n.hint != "" {
+		str = lg.JoinVertical(lg.Left, str, s.Copy().MarginTop(0).Bold(true).Render(n.hint))
+	}
 	footer := s.Faint(true).Render("esc: back to item navigation")
 
 	return lg.JoinVertical(lg.Left, prompt, str, footer)
 }
 
-func (n *nagScreen) Update(msg tea.Msg) (mode, bool) {
+func (n *nagScreen) Update(msg tea.Msg) (mode, bool, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
-		switch msg.String() {
-		case "esc":
-			return navigation, false
-		default:
+		if msg.String() == "esc" {
+			return navigation, false, nil
+		}
+
+		switch n.challenge {
+		case lettersChallenge:
 			// process keystroke
 			if msg.String() == string(n.nagText[0]) {
 				n.nagText = n.nagText[1:]
 			}
 			if len(n.nagText) == 0 {
-				return navigation, true
+				return navigation, true, nil
+			}
+		case countChallenge:
+			if msg.String() != "enter" {
+				break
+			}
+			if strings.TrimSpace(n.answer.Value()) == strconv.Itoa(n.count) {
+				return navigation, true, nil
+			}
+			n.hint = "That's not it. Count again."
+			n.answer.SetValue("")
+			return nag, false, nil
+		case justifyChallenge:
+			if msg.String() != "enter" {
+				break
+			}
+			if len(strings.Fields(n.answer.Value())) >= n.words() {
+				return navigation, true, nil
 			}
-			return nag, false
+			n.hint = fmt.Sprintf("At least %d words, please.", n.words())
+			return nag, false, nil
+		case cooldownChallenge:
+			if msg.String() == "enter" && !time.Now().Before(n.until) {
+				return navigation, true, nil
+			}
+			return nag, false, nil
 		}
 	}
-	return nag, false
+
+	if n.challenge == countChallenge || n.challenge == justifyChallenge {
+		var cmd tea.Cmd
+		n.answer, cmd = n.answer.Update(msg)
+		return nag, false, cmd
+	}
+	return nag, false, nil
+}
+
+// cooling reports whether the nag is a cooldown still counting down.
+func (n *nagScreen) cooling(now time.Time) bool {
+	return n.challenge == cooldownChallenge && now.Before(n.until)
 }
 
-func (t *tui) setNag(prompt string, size int, exit mode) {
-	t.nag = NewNag(prompt, size, exit)
+func (t *tui) setNag(prompt string, count, level int) tea.Cmd {
+	t.nag = NewNag(prompt, t.nagPolicy.challenge, count, level)
 	t.mode = nag
+	if t.nag.challenge == justifyChallenge || t.nag.challenge == countChallenge {
+		return textinput.Blink
+	}
+	return t.keepTicking()
+}
+
+// nagCount counts the items weighed against the nag policy's threshold,
+// and describes where they are.
+func (t *tui) nagCount() (int, string) {
+	switch t.nagPolicy.scope {
+	case projectNagScope:
+		if t.scope == "" {
+			return len(t.inboxItems()), "in the inbox"
+		}
+		n := 0
+		for _, item := range t.items {
+			pending := item.Satus() == tuido.Open || item.Satus() == tuido.Ongoing
+			if pending && item.Active() && t.scope.matches(item) {
+				n++
+			}
+		}
+		return n, "in " + string(t.scope)
+	case ongoingNagScope:
+		n := 0
+		for _, item := range t.items {
+			if item.Satus() == tuido.Ongoing {
+				n++
+			}
+		}
+		return n, "in progress"
+	}
+	return len(t.renderSelection), "on your plate"
+}
+
+// overrideNag creates the new item that a nag was overcome for, noting
+// the override to be logged once the item is saved.
+func (t *tui) overrideNag() {
+	_, where := t.nagCount()
+	t.override = &override{
+		time:      time.Now(),
+		challenge: t.nag.challenge,
+		scope:     t.nagPolicy.scope,
+		where:     where,
+		count:     t.nag.count,
+	}
+	if t.nag.challenge == justifyChallenge {
+		t.override.reason = strings.TrimSpace(t.nag.answer.Value())
+	}
+
+	t.createNewItem()
+	if t.override.reason != "" {
+		t.descEditor.lines = append(t.descEditor.lines, newDescriptionLine("why now: "+t.override.reason))
+	}
+}
+
+// override is a record of a nag overcome to create an item anyway, kept
+// for later reflection.
+type override struct {
+	time      time.Time
+	challenge nagChallenge
+	scope     nagScope
+	// where describes the counted items, eg "in +release"
+	where string
+	count int
+	// reason is the justification written, if any
+	reason string
+
+	// item is the location of the item created, and text its text
+	item string
+	text string
+}
+
+// overridesPath is the location of the append-only log of overridden
+// nags. It is set in `init()`, alongside the tuido directory.
+var overridesPath string
+
+// String formats the override as a tab separated log line.
+func (o override) String() string {
+	return strings.Join([]string{
+		o.time.Format(time.RFC3339),
+		string(o.scope),
+		fmt.Sprintf("%d %s", o.count, o.where),
+		string(o.challenge),
+		o.item,
+		o.text,
+		o.reason,
+	}, "\t")
+}
+
+// appendOverride adds o to the end of the override log at path.
+func appendOverride(path string, o override) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(o.String() + "\n")
+	return err
 }
 
 var fibs map[int]int = map[int]int{}
@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+	"github.com/nilock/tuido/tuido"
+)
+
+// notification is a message to the user about a timer event, which
+// may arrive while the terminal is in the background.
+type notification struct {
+	title string
+	body  string
+}
+
+// notifier delivers notifications somewhere the user will see them.
+type notifier interface {
+	notify(n notification) error
+}
+
+// notifiers returns the sinks named in a comma separated list, eg
+// "bell,dbus". Unknown names are reported as an error, alongside the
+// sinks that could be made.
+func notifiers(list string) ([]notifier, error) {
+	sinks := []notifier{}
+	unknown := []string{}
+
+	for _, name := range strings.Split(list, ",") {
+		switch strings.TrimSpace(name) {
+		case "", "none":
+		case "bell":
+			sinks = append(sinks, bell{os.Stdout})
+		case "osc9":
+			sinks = append(sinks, osc9{os.Stdout})
+		case "osc777":
+			sinks = append(sinks, osc777{os.Stdout})
+		case "dbus":
+			sinks = append(sinks, desktop{})
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return sinks, fmt.Errorf("unknown notification sinks: %s", strings.Join(unknown, ", "))
+	}
+	return sinks, nil
+}
+
+// terminalMu serializes the terminal sinks, whose notifications are sent
+// from command goroutines.
+var terminalMu sync.Mutex
+
+// writeTerminal writes seq to w in a single write. The renderer also
+// writes each frame to the terminal in a single write, and the terminal
+// driver does not interleave the bytes of separate writes, so seq lands
+// whole between two frames. Bells and OSC notifications don't move the
+// cursor, so the frames around them draw as usual.
+func writeTerminal(w io.Writer, seq string) error {
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+
+	_, err := w.Write([]byte(seq))
+	return err
+}
+
+// bell rings the terminal bell, which most terminals and multiplexers
+// surface as an urgency hint on a background window.
+type bell struct{ w io.Writer }
+
+func (b bell) notify(notification) error {
+	return writeTerminal(b.w, "\a")
+}
+
+// osc9 sends a notification with the OSC 9 escape sequence, understood
+// by iTerm2, Windows Terminal, kitty, and others.
+type osc9 struct{ w io.Writer }
+
+func (o osc9) notify(n notification) error {
+	msg := n.title
+	if n.body != "" {
+		msg += ": " + n.body
+	}
+	return writeTerminal(o.w, "\x1b]9;"+escapeSafe(msg)+"\a")
+}
+
+// osc777 sends a notification with the OSC 777 escape sequence,
+// understood by urxvt, foot, and VTE based terminals.
+type osc777 struct{ w io.Writer }
+
+func (o osc777) notify(n notification) error {
+	return writeTerminal(o.w,
+		"\x1b]777;notify;"+strings.ReplaceAll(escapeSafe(n.title), ";", ",")+";"+escapeSafe(n.body)+"\a")
+}
+
+// escapeSafe strips control characters, which would end an escape
+// sequence early.
+func escapeSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// desktop sends freedesktop notifications over the D-Bus session bus.
+type desktop struct{}
+
+func (desktop) notify(n notification) error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	obj := conn.Object("org.freedesktop.Notifications", "/org/freedesktop/Notifications")
+	return obj.Call("org.freedesktop.Notifications.Notify", 0,
+		"tuido",                   // app_name
+		uint32(0),                 // replaces_id
+		"",                        // app_icon
+		n.title,                   // summary
+		n.body,                    // body
+		[]string{},                // actions
+		map[string]dbus.Variant{}, // hints
+		int32(-1),                 // expire_timeout: the server's default
+	).Err
+}
+
+// notifyErrMsg reports a failure to deliver a notification.
+type notifyErrMsg struct{ err error }
+
+// notify returns a command delivering n to each configured sink. A sink
+// that fails doesn't keep the others from trying, and the failures are
+// reported together.
+func (t tui) notify(n notification) tea.Cmd {
+	sinks := t.notifiers
+	if len(sinks) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := notifyAll(sinks, n); err != nil {
+			return notifyErrMsg{err}
+		}
+		return nil
+	}
+}
+
+// notifyAll delivers n to every sink, returning their errors joined.
+func notifyAll(sinks []notifier, n notification) error {
+	errs := []error{}
+	for _, s := range sinks {
+		if err := s.notify(n); err != nil {
+			errs = append(errs, fmt.Errorf("error sending notification: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// dueNotifications returns notifications for the unfinished items that
+// came due after since, and up to now.
+func dueNotifications(items []*tuido.Item, since, now time.Time) []notification {
+	ret := []notification{}
+	for _, item := range items {
+		if s := item.Satus(); s != tuido.Open && s != tuido.Ongoing {
+			continue
+		}
+		due := item.Due()
+		if due == nil || due.IsZero() || !due.After(since) || due.After(now) {
+			continue
+		}
+		ret = append(ret, notification{title: "Due now", body: item.Text()})
+	}
+	return ret
+}
+
+// notifyDue returns a command notifying of items that have come due
+// since the last check.
+func (t *tui) notifyDue(now time.Time) tea.Cmd {
+	cmds := []tea.Cmd{}
+	for _, n := range dueNotifications(t.items, t.dueChecked, now) {
+		cmds = append(cmds, t.notify(n))
+	}
+	t.dueChecked = now
+	return tea.Batch(cmds...)
+}
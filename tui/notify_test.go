@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestNotifiers(t *testing.T) {
+	sinks, err := notifiers("bell, osc9,osc777,dbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 4 {
+		t.Fatalf("expected 4 sinks, but found %d", len(sinks))
+	}
+	if _, ok := sinks[0].(bell); !ok {
+		t.Errorf("expected a bell, but found %T", sinks[0])
+	}
+	if _, ok := sinks[1].(osc9); !ok {
+		t.Errorf("expected an osc9 sink, but found %T", sinks[1])
+	}
+	if _, ok := sinks[2].(osc777); !ok {
+		t.Errorf("expected an osc777 sink, but found %T", sinks[2])
+	}
+	if _, ok := sinks[3].(desktop); !ok {
+		t.Errorf("expected a desktop sink, but found %T", sinks[3])
+	}
+
+	for _, list := range []string{"", "none"} {
+		if sinks, err := notifiers(list); err != nil || len(sinks) != 0 {
+			t.Errorf("expected no sinks for %q, but found %d (%v)", list, len(sinks), err)
+		}
+	}
+
+	sinks, err = notifiers("bell,pager")
+	if err == nil {
+		t.Error("expected an error for the unknown sink pager")
+	}
+	if len(sinks) != 1 {
+		t.Errorf("expected the bell alongside the error, but found %d sinks", len(sinks))
+	}
+}
+
+func TestTerminalSinks(t *testing.T) {
+	n := notification{title: "Break; over", body: "back to\nwork"}
+
+	tests := []struct {
+		sink     func(*bytes.Buffer) notifier
+		expected string
+	}{
+		{func(b *bytes.Buffer) notifier { return bell{b} }, "\a"},
+		{func(b *bytes.Buffer) notifier { return osc9{b} }, "\x1b]9;Break; over: back towork\a"},
+		{func(b *bytes.Buffer) notifier { return osc777{b} }, "\x1b]777;notify;Break, over;back towork\a"},
+	}
+
+	for _, test := range tests {
+		var b bytes.Buffer
+		sink := test.sink(&b)
+		if err := sink.notify(n); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != test.expected {
+			t.Errorf("expected %T to write %q, but found %q", sink, test.expected, b.String())
+		}
+	}
+}
+
+// failing is a sink that can't deliver notifications.
+type failing struct{ name string }
+
+func (f failing) notify(notification) error {
+	return errors.New(f.name + " is unavailable")
+}
+
+func TestNotifyAll(t *testing.T) {
+	var b bytes.Buffer
+	sinks := []notifier{failing{"dbus"}, bell{&b}, failing{"pager"}}
+
+	err := notifyAll(sinks, notification{title: "Break over"})
+	if b.String() != "\a" {
+		t.Errorf("expected the bell to ring despite the failing sinks, but found %q", b.String())
+	}
+	if err == nil || !strings.Contains(err.Error(), "dbus is unavailable") || !strings.Contains(err.Error(), "pager is unavailable") {
+		t.Errorf("expected both failures to be reported, but found %v", err)
+	}
+
+	if err := notifyAll([]notifier{bell{&b}}, notification{title: "Break over"}); err != nil {
+		t.Errorf("expected no error, but found %v", err)
+	}
+}
+
+func TestDesktopNotify(t *testing.T) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		t.Skip("no D-Bus session bus")
+	}
+
+	if err := (desktop{}).notify(notification{title: "tuido", body: "test notification"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDueNotifications(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	since := now.Add(-5 * time.Minute)
+
+	items := []*tuido.Item{}
+	for i, raw := range []string{
+		"[ ] due now #due=2026-10-19T11:59",
+		"[@] ongoing and due #due=2026-10-19T12:00",
+		"[ ] due before the last check #due=2026-10-19T11:50",
+		"[ ] due later #due=2026-10-19T12:01",
+		"[x] done already #due=2026-10-19T11:59",
+		"[ ] no due date",
+	} {
+		item := tuido.New("list.xit", i+1, raw)
+		items = append(items, &item)
+	}
+
+	notifs := dueNotifications(items, since, now)
+	if len(notifs) != 2 {
+		t.Fatalf("expected 2 notifications, but found %d: %v", len(notifs), notifs)
+	}
+	for i, n := range notifs {
+		if n.title != "Due now" || n.body != items[i].Text() {
+			t.Errorf("expected a due notification for %q, but found %v", items[i].Text(), n)
+		}
+	}
+}
@@ -1,45 +1,259 @@
 package tui
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
 	"github.com/nilock/tuido/tuido"
 )
 
+// peekScreen shows an item in the context of its source file, which
+// can be scrolled, searched, and stepped through item by item.
 type peekScreen struct {
-	item tuido.Item
+	item *tuido.Item
+	// items are the items from the same file, in order of appearance
+	items []*tuido.Item
+
+	plain []string
+
+	// cursor is the index of the current line, and top is the index
+	// of the first line on screen
+	cursor int
+	top    int
+
+	search textinput.Model
+	query  string
 }
 
-func (p *peekScreen) View(h, w int, footer func() string) string {
-	foot := footer()
+func (t *tui) setPeekMode() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil && len(t.items) != 0 {
+		item = t.items[0]
+	}
+	if item == nil {
+		return nil
+	}
+	t.peekAt(item, item.Line()-1)
+	return nil
+}
+
+// peekAt opens the peek screen on item's file, with the cursor on line
+// (counting from 0).
+func (t *tui) peekAt(item *tuido.Item, line int) {
+	plain, err := tuido.Source(item.File())
+	if err != nil {
+		t.err = err
+		return
+	}
+
+	items := []*tuido.Item{}
+	for _, i := range t.items {
+		if i.File() == item.File() {
+			items = append(items, i)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Line() < items[j].Line() })
+
+	search := textinput.New()
+	search.Prompt = "/"
 
-	availableHeight := h - lg.Height(foot)
+	t.peek = peekScreen{
+		item:   item,
+		items:  items,
+		plain:  plain,
+		search: search,
+	}
+	t.peek.jumpTo(line, t.peekHeight())
+	t.mode = peek
+}
 
-	bodyPadding := 2
+// peekHeight is the number of file lines shown in the peek screen.
+func (t tui) peekHeight() int {
+	return max(1, t.h-lg.Height(t.footer())-2)
+}
 
-	peekBody, n := p.item.GetContext(availableHeight)
-	peekBody = lg.
-		NewStyle().
-		Padding(bodyPadding).
-		Render(peekBody)
+// scrollTo moves the cursor to line, scrolling as little as possible
+// to keep it on screen.
+func (p *peekScreen) scrollTo(line, height int) {
+	p.cursor = max(0, min(line, len(p.plain)-1))
+	if p.cursor < p.top {
+		p.top = p.cursor
+	}
+	if p.cursor >= p.top+height {
+		p.top = p.cursor - height + 1
+	}
+}
 
-	st := lg.NewStyle().
-		Foreground(lg.Color("#a0f0a0"))
+// jumpTo moves the cursor to line, centring it on screen.
+func (p *peekScreen) jumpTo(line, height int) {
+	p.cursor = max(0, min(line, len(p.plain)-1))
+	p.top = max(0, min(p.cursor-height/2, len(p.plain)-height))
+}
 
-	pointer := strings.Repeat("  │\n", n+bodyPadding)
-	pointer += ">>│"
-	pointer += strings.Repeat("\n  │", lg.Height(peekBody)-lg.Height(pointer))
+// itemAt returns the item on the cursor's line, if any.
+func (p peekScreen) itemAt(line int) *tuido.Item {
+	for _, item := range p.items {
+		if item.Line() == line+1 {
+			return item
+		}
+	}
+	return nil
+}
 
-	pointer = st.Render(pointer)
+// nextItem moves to the following (or with a negative step, preceding)
+// item in the file.
+func (p *peekScreen) nextItem(step, height int) {
+	next := -1
+	for i, item := range p.items {
+		if step > 0 && item.Line() > p.cursor+1 {
+			next = i
+			break
+		}
+		if step < 0 && item.Line() < p.cursor+1 {
+			next = i
+		}
+	}
+	if next < 0 {
+		return
+	}
+	p.item = p.items[next]
+	p.jumpTo(p.item.Line()-1, height)
+}
+
+// nextMatch moves to the next (or with a negative step, previous)
+// line containing the search query, wrapping around the file.
+func (p *peekScreen) nextMatch(step, height int) bool {
+	query := strings.ToLower(p.query)
+	if query == "" {
+		return false
+	}
+	for n := 1; n <= len(p.plain); n++ {
+		line := ((p.cursor+step*n)%len(p.plain) + len(p.plain)) % len(p.plain)
+		if strings.Contains(strings.ToLower(p.plain[line]), query) {
+			p.jumpTo(line, height)
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tui) updatePeek(msg tea.Msg) tea.Cmd {
+	p := &t.peek
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	height := t.peekHeight()
+
+	if p.search.Focused() {
+		switch key.String() {
+		case "esc":
+			p.search.Blur()
+		case "enter":
+			p.search.Blur()
+			p.query = p.search.Value()
+			t.err = nil
+			if !p.nextMatch(1, height) && p.query != "" {
+				t.err = fmt.Errorf("not found: %s", p.query)
+			}
+		default:
+			var cmd tea.Cmd
+			p.search, cmd = p.search.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	t.err = nil
+	switch key.String() {
+	case "esc", "enter", "q":
+		t.mode = navigation
+		t.selectItem(p.item)
+	case "up", "k":
+		p.scrollTo(p.cursor-1, height)
+	case "down", "j":
+		p.scrollTo(p.cursor+1, height)
+	case "pgup", "ctrl+u":
+		p.top = max(0, p.top-height)
+		p.scrollTo(p.cursor-height, height)
+	case "pgdown", "ctrl+d", " ":
+		p.top = max(0, min(p.top+height, len(p.plain)-height))
+		p.scrollTo(p.cursor+height, height)
+	case "home", "g":
+		p.scrollTo(0, height)
+	case "end", "G":
+		p.scrollTo(len(p.plain)-1, height)
+	case "]", "tab":
+		p.nextItem(1, height)
+	case "[", "shift+tab":
+		p.nextItem(-1, height)
+	case "/":
+		p.search.SetValue("")
+		p.search.Focus()
+	case "n":
+		p.nextMatch(1, height)
+	case "N":
+		p.nextMatch(-1, height)
+	case "o":
+		return t.openInEditor()
+	}
+
+	if item := p.itemAt(p.cursor); item != nil {
+		p.item = item
+	}
+	return nil
+}
+
+func (t tui) peekView() string {
+	p := t.peek
+	height := t.peekHeight()
+
+	gutterWidth := len(fmt.Sprint(len(p.plain)))
+	faint := lg.NewStyle().Faint(true)
+	pointer := lg.NewStyle().Foreground(lg.Color("#a0f0a0"))
+	current := lg.NewStyle().Reverse(true)
+	match := lg.NewStyle().Underline(true)
+	query := strings.ToLower(p.query)
+
+	// only the lines on screen are highlighted. On error, plain text is shown.
+	highlighted, _ := tuido.Highlighted(p.item.File(), p.top, p.top+height)
+
+	rows := []string{}
+	for line := p.top; line < min(p.top+height, len(p.plain)); line++ {
+		marker := "  "
+		if p.itemAt(line) != nil {
+			marker = "• "
+		}
+		gutter := faint.Render(fmt.Sprintf("%*d ", gutterWidth, line+1)) + pointer.Render(marker)
+
+		// the highlighted source carries its own colors, which would
+		// clobber any styling, so the current line is restyled as plain text
+		text := p.plain[line]
+		if line-p.top < len(highlighted) {
+			text = highlighted[line-p.top]
+		}
+		if line == p.cursor {
+			gutter = fmt.Sprintf("%*d ", gutterWidth, line+1) + pointer.Render(">>")
+			text = current.Render(p.plain[line])
+		} else if query != "" && strings.Contains(strings.ToLower(p.plain[line]), query) {
+			text = match.Render(p.plain[line])
+		}
+		rows = append(rows, gutter+" "+text)
+	}
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
 
-	bodyWithPointer := lg.JoinHorizontal(lg.Top, pointer, peekBody)
+	body := lg.NewStyle().MaxWidth(t.w).Margin(1, 0, 1, 1).Render(strings.Join(rows, "\n"))
 
-	// pad bottom
-	availableHeight = h - (lg.Height(peekBody) + lg.Height(foot))
-	for i := 0; i < availableHeight; i++ {
-		bodyWithPointer += "\n"
+	footer := t.footer()
+	if p.search.Focused() {
+		footer = p.search.View()
 	}
 
-	return lg.JoinVertical(lg.Left, bodyWithPointer, foot)
+	return lg.JoinVertical(lg.Left, body, footer)
 }
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// planScreen pulls pending items into the today list, against the
+// configured daily capacity.
+type planScreen struct {
+	// candidates are the leftovers, then the items already planned for
+	// today, then the rest of the pending items
+	candidates []*tuido.Item
+	selected   int
+
+	// leftovers were planned for an earlier day and not finished. Those
+	// not planned for today by the time the screen closes are unplanned.
+	leftovers map[*tuido.Item]bool
+}
+
+func (t *tui) setPlanMode() {
+	t.mode = planning
+	t.plan = newPlan(t.items, nil, time.Now())
+}
+
+// newPlan gathers the pending items that could be planned for the local
+// day of now.
+func newPlan(items, leftovers []*tuido.Item, now time.Time) planScreen {
+	p := planScreen{leftovers: map[*tuido.Item]bool{}}
+	p.candidates = append(p.candidates, leftovers...)
+	for _, item := range leftovers {
+		p.leftovers[item] = true
+	}
+
+	planned, rest := []*tuido.Item{}, []*tuido.Item{}
+	for _, item := range items {
+		pending := item.Satus() == tuido.Open || item.Satus() == tuido.Ongoing
+		if !pending || !item.Active() || p.leftovers[item] {
+			continue
+		}
+		if item.PlannedFor(now) {
+			planned = append(planned, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	p.candidates = append(p.candidates, planned...)
+	p.candidates = append(p.candidates, rest...)
+	return p
+}
+
+// rollOver handles the leftovers of earlier days' plans, according to the
+// rollover config, at launch until they have been dealt with for the day.
+// A rollover prompt that is quit without being finished is shown again on
+// the next launch.
+func (t *tui) rollOver() {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if !t.state.rolledOver.Before(today) {
+		return
+	}
+
+	leftovers := tuido.Leftovers(t.items, now)
+	if len(leftovers) == 0 {
+		t.state.rolledOver = now
+		return
+	}
+
+	if t.config.rollover == "auto" {
+		for _, item := range leftovers {
+			if err := item.Plan(now); err != nil {
+				t.err = err
+			}
+		}
+		t.state.rolledOver = now
+		t.notifs = append(t.notifs, fmt.Sprintf("%d unfinished item(s) from earlier plans were rolled over to today", len(leftovers)))
+		return
+	}
+
+	t.mode = planning
+	t.plan = newPlan(t.items, leftovers, now)
+}
+
+// endPlan unplans the leftovers that were not planned again for today,
+// which settles the day's rollover.
+func (t *tui) endPlan() {
+	now := time.Now()
+	for item := range t.plan.leftovers {
+		if !item.PlannedFor(now) {
+			if err := item.Unplan(); err != nil {
+				t.err = err
+			}
+		}
+	}
+	if len(t.plan.leftovers) > 0 {
+		t.state.rolledOver = now
+	}
+
+	t.mode = navigation
+	t.repopulateKeepingSelection()
+}
+
+// capacity returns the configured work that fits in a day.
+func (t tui) capacity() (time.Duration, error) {
+	capacity, err := tuido.ParseEffort(t.config.capacity)
+	if err != nil {
+		return 0, fmt.Errorf("invalid capacity config: %w", err)
+	}
+	return capacity, nil
+}
+
+// todayLabel names the today tab with the estimates of today's items
+// against the daily capacity, in red when over-committed.
+func (t tui) todayLabel() string {
+	estimated, _ := tuido.PlannedEffort(t.items, time.Now())
+	if estimated == 0 {
+		return string(today)
+	}
+
+	capacity, err := t.capacity()
+	if err != nil {
+		return fmt.Sprintf("%s %s", today, tuido.FormatEffort(estimated))
+	}
+	label := fmt.Sprintf("%s %s/%s", today, tuido.FormatEffort(estimated), tuido.FormatEffort(capacity))
+	if estimated > capacity {
+		return lg.NewStyle().Foreground(lg.Color("#ff2222")).Render(label)
+	}
+	return label
+}
+
+func (t *tui) updatePlan(msg tea.Msg) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return
+	}
+	p := &t.plan
+
+	switch key.String() {
+	case "up", "k":
+		p.selected = max(0, p.selected-1)
+	case "down", "j":
+		p.selected = max(0, min(len(p.candidates)-1, p.selected+1))
+	case " ", "enter":
+		if p.selected < len(p.candidates) {
+			item := p.candidates[p.selected]
+			if item.PlannedFor(time.Now()) {
+				t.err = item.Unplan()
+			} else {
+				t.err = item.Plan(time.Now())
+			}
+		}
+	case "esc", "q", "P":
+		t.endPlan()
+	}
+}
+
+func (t tui) planView() string {
+	p := t.plan
+	now := time.Now()
+	faint := lg.NewStyle().Faint(true)
+	heading := lg.NewStyle().Bold(true)
+	warning := lg.NewStyle().Bold(true).Foreground(lg.Color("#ff2222"))
+
+	estimated, unestimated := tuido.PlannedEffort(t.items, now)
+	load := fmt.Sprintf("%s estimated", tuido.FormatEffort(estimated))
+	capacity, err := t.capacity()
+	if err == nil {
+		load += fmt.Sprintf(" of %s capacity", tuido.FormatEffort(capacity))
+	}
+	if err == nil && estimated > capacity {
+		load += "  " + warning.Render(fmt.Sprintf("over-committed by %s", tuido.FormatEffort(estimated-capacity)))
+	}
+	if unestimated > 0 {
+		load += faint.Render(fmt.Sprintf("  (%d planned item(s) without an #estimate)", unestimated))
+	}
+
+	rows := []string{heading.Render("Plan for " + now.Format("Monday, Jan 2")), load, ""}
+	if len(p.leftovers) > 0 {
+		rows = append(rows, faint.Render("Unfinished items from earlier plans are listed first. Those not planned again are dropped from the plan when you're done."), "")
+	}
+
+	// leave room for the heading and hints, keeping the cursor in view
+	room := max(1, t.h-12)
+	first := max(0, min(p.selected-room/2, len(p.candidates)-room))
+	for i := first; i < len(p.candidates) && i < first+room; i++ {
+		item := p.candidates[i]
+
+		mark := " "
+		if item.PlannedFor(now) {
+			mark = "★"
+		}
+		row := mark + " " + item.String()
+		if estimate := item.Estimate(); estimate != nil {
+			row += faint.Render("  " + tuido.FormatEffort(*estimate))
+		}
+		if planned := item.Planned(); p.leftovers[item] && !item.PlannedFor(now) && planned != nil {
+			row += faint.Render("  · planned " + planned.Format("Mon Jan 2"))
+		}
+
+		if i == p.selected {
+			rows = append(rows, "> "+lg.NewStyle().Bold(true).Render(row))
+		} else {
+			rows = append(rows, "  "+row)
+		}
+	}
+	if len(p.candidates) == 0 {
+		rows = append(rows, faint.Render("  nothing pending to plan"))
+	}
+
+	rows = append(rows, "", faint.Render("★: planned   [space]: plan/unplan for today   [esc]: done   planned items are listed in the today tab"))
+	if t.err != nil {
+		rows = append(rows, warning.Render(t.err.Error()))
+	}
+
+	return lg.NewStyle().Margin(1, 2).Render(lg.JoinVertical(lg.Left, rows...))
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nilock/tuido/tuido"
+)
+
+func TestRollOverUntilSettled(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	raw := "[ ] leftover #planned=" + yesterday
+	file := filepath.Join(t.TempDir(), "list.xit")
+	if err := os.WriteFile(file, []byte(raw+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	item := tuido.New(file, 1, raw)
+
+	cfg := runConfig
+	cfg.rollover = "prompt"
+	model := tui{config: cfg, items: []*tuido.Item{&item}, mode: navigation}
+	model.state.lastSession = time.Now()
+
+	// a prompt quit without being finished is shown again
+	for i := 0; i < 2; i++ {
+		model.mode = navigation
+		model.rollOver()
+		if model.mode != planning {
+			t.Fatalf("expected launch %d to prompt for the rollover", i+1)
+		}
+	}
+
+	model.endPlan()
+	if model.state.rolledOver.IsZero() {
+		t.Error("expected the rollover to be settled")
+	}
+	if item.Planned() != nil {
+		t.Errorf("expected the leftover to be unplanned, but found %q", item.Text())
+	}
+
+	model.mode = navigation
+	model.rollOver()
+	if model.mode != navigation {
+		t.Error("expected no further prompt once the rollover was settled")
+	}
+}
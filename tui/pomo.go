@@ -0,0 +1,373 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+type pomoPhase int
+
+const (
+	// pomoSetup asks for the length of the focus periods
+	pomoSetup pomoPhase = iota
+	pomoWork
+	pomoBreak
+	pomoLongBreak
+	// pomoReady waits between a break and the next focus period
+	pomoReady
+)
+
+func (p pomoPhase) String() string {
+	switch p {
+	case pomoWork:
+		return "focus"
+	case pomoBreak:
+		return "short break"
+	case pomoLongBreak:
+		return "long break"
+	case pomoReady:
+		return "break over"
+	}
+	return "new pomodoro"
+}
+
+// pomodoro is a cycle of focus periods and breaks, on one item.
+//
+// Phases are timed by the wall clock rather than by counting ticks, so
+// that they keep time across a suspended process or machine.
+type pomodoro struct {
+	item  *tuido.Item
+	phase pomoPhase
+	// work is the length of focus periods, as chosen at setup
+	work time.Duration
+
+	// length is the length of the current phase
+	length time.Duration
+	// started is when the current phase began, and resumed is when it
+	// last began running, after any pause
+	started time.Time
+	resumed time.Time
+	// elapsed is the time the phase ran for before its last pause
+	elapsed time.Duration
+	paused  bool
+
+	// cycles is the number of completed focus periods
+	cycles int
+}
+
+// running returns how long the current phase has run for, less pauses.
+func (p pomodoro) running(now time.Time) time.Duration {
+	if p.paused {
+		return p.elapsed
+	}
+	return p.elapsed + now.Sub(p.resumed)
+}
+
+// remaining returns the time left in the current phase.
+func (p pomodoro) remaining(now time.Time) time.Duration {
+	if p.phase == pomoSetup || p.phase == pomoReady {
+		return 0
+	}
+	if r := p.length - p.running(now); r > 0 {
+		return r
+	}
+	return 0
+}
+
+// timed reports whether the clock is running down.
+func (p pomodoro) timed() bool {
+	return !p.paused && (p.phase == pomoWork || p.phase == pomoBreak || p.phase == pomoLongBreak)
+}
+
+// pomoDurations returns the configured lengths of breaks, and the
+// number of focus periods between long breaks.
+func (cfg config) pomoDurations() (short, long time.Duration, cycles int) {
+	short, err := time.ParseDuration(cfg.pomoBreak)
+	if err != nil {
+		short = 5 * time.Minute
+	}
+	long, err = time.ParseDuration(cfg.pomoLongBreak)
+	if err != nil {
+		long = 15 * time.Minute
+	}
+	cycles, err = strconv.Atoi(cfg.pomoCycles)
+	if err != nil || cycles < 1 {
+		cycles = 4
+	}
+	return short, long, cycles
+}
+
+func (t *tui) setPomoMode() tea.Cmd {
+	item := t.currentSelection()
+	if item == nil {
+		return nil
+	}
+
+	// the pomodoro takes over from the stopwatch
+	t.stopClock(time.Now())
+
+	t.mode = pomo
+	t.pomodoro = pomodoro{item: item}
+
+	t.pomoEditor.Placeholder = "25"
+	if work, err := time.ParseDuration(t.config.pomoWork); err == nil {
+		t.pomoEditor.Placeholder = fmt.Sprint(work.Minutes())
+	}
+	t.pomoEditor.Focus()
+	t.pomoEditor.SetValue("")
+
+	return nil
+}
+
+// startPomo begins the first focus period, with the length in minutes
+// entered in the pomoEditor, or else the configured length.
+func (t *tui) startPomo() tea.Cmd {
+	work, err := time.ParseDuration(t.config.pomoWork)
+	if err != nil {
+		work = 25 * time.Minute
+	}
+
+	if v := t.pomoEditor.Value(); v != "" {
+		minutes, err := strconv.ParseFloat(v, 64)
+		if err != nil || minutes <= 0 {
+			t.err = fmt.Errorf("invalid pomodoro length: %s", v)
+			return nil
+		}
+		work = time.Duration(minutes * float64(time.Minute))
+	}
+
+	t.err = nil
+	t.pomodoro.work = work
+	return t.startPhase(pomoWork, time.Now())
+}
+
+// startPhase begins the given phase at now, returning the command that
+// keeps the clock ticking, if it is not already.
+func (t *tui) startPhase(phase pomoPhase, now time.Time) tea.Cmd {
+	p := &t.pomodoro
+	short, long, _ := t.config.pomoDurations()
+
+	p.phase = phase
+	p.paused = false
+	p.started = now
+	p.resumed = now
+	p.elapsed = 0
+	switch phase {
+	case pomoWork:
+		p.length = p.work
+	case pomoBreak:
+		p.length = short
+	case pomoLongBreak:
+		p.length = long
+	default:
+		p.length = 0
+	}
+	return t.keepTicking()
+}
+
+// togglePause pauses or resumes the clock.
+func (t *tui) togglePause(now time.Time) tea.Cmd {
+	p := &t.pomodoro
+	if p.paused {
+		p.paused = false
+		p.resumed = now
+		return t.keepTicking()
+	}
+	p.elapsed = p.running(now)
+	p.paused = true
+	return nil
+}
+
+// finishFocus credits the time focused in the current work phase to the
+// pomodoro's item, and records the session in the session log. Focus is
+// counted up to the planned length, even if the phase ended while the
+// program was suspended.
+func (t *tui) finishFocus(now time.Time) {
+	p := &t.pomodoro
+	if p.phase != pomoWork {
+		return
+	}
+
+	focused := p.running(now)
+	if focused > p.length {
+		focused = p.length
+	}
+	end := now
+	if !p.paused && p.remaining(now) == 0 {
+		// the phase ran out, which may have been some time before now
+		end = p.resumed.Add(p.length - p.elapsed)
+	}
+
+	if focused < time.Second {
+		return
+	}
+
+	err := appendSession(sessionsPath, session{
+		item:    p.item.Location(),
+		text:    p.item.Text(),
+		start:   p.started,
+		end:     end,
+		planned: p.length,
+		actual:  focused,
+	})
+	if err != nil {
+		t.err = fmt.Errorf("error logging pomodoro: %w", err)
+	}
+	if err := p.item.IncrementTimeSpent(int(focused.Seconds())); err != nil {
+		t.err = fmt.Errorf("error recording pomodoro time: %w", err)
+	}
+}
+
+// tickPomo updates the pomodoro clock, moving to the next phase when
+// the current one has run out.
+func (t *tui) tickPomo(now time.Time) tea.Cmd {
+	p := &t.pomodoro
+	if t.mode != pomo || !p.timed() || p.remaining(now) > 0 {
+		return nil
+	}
+
+	// the phase's end time, rather than now, begins the next phase
+	end := p.resumed.Add(p.length - p.elapsed)
+
+	switch p.phase {
+	case pomoWork:
+		t.finishFocus(now)
+		p.cycles++
+		_, _, cycles := t.config.pomoDurations()
+		next := pomoBreak
+		if p.cycles%cycles == 0 {
+			next = pomoLongBreak
+		}
+		done := t.notify(notification{
+			title: "Pomodoro complete",
+			body:  fmt.Sprintf("%s: time for a %s", p.item.Text(), next),
+		})
+		return tea.Batch(t.startPhase(next, end), done)
+	case pomoBreak, pomoLongBreak:
+		over := t.notify(notification{
+			title: "Break over",
+			body:  "ready for the next pomodoro on " + p.item.Text(),
+		})
+		return tea.Batch(t.startPhase(pomoReady, end), over)
+	}
+	return nil
+}
+
+// stopPomo leaves pomo mode, crediting any focused time.
+func (t *tui) stopPomo() {
+	t.finishFocus(time.Now())
+	t.pomoEditor.Reset()
+	t.mode = navigation
+	t.selectItem(t.pomodoro.item)
+}
+
+func (t *tui) updatePomo(msg tea.Msg) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	p := &t.pomodoro
+
+	if p.phase == pomoSetup {
+		switch key.String() {
+		case "enter":
+			return t.startPomo()
+		case "esc":
+			t.stopPomo()
+		default:
+			// only accept numbers of minutes
+			if r := key.Runes; key.Type == tea.KeyRunes && !strings.ContainsAny(string(r), "0123456789.") {
+				return nil
+			}
+			var cmd tea.Cmd
+			t.pomoEditor, cmd = t.pomoEditor.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch key.String() {
+	case " ", "p":
+		if p.phase != pomoReady {
+			return t.togglePause(time.Now())
+		}
+	case "s":
+		if p.phase == pomoBreak || p.phase == pomoLongBreak {
+			return t.startPhase(pomoReady, time.Now())
+		}
+	case "enter":
+		if p.phase == pomoReady {
+			return t.startPhase(pomoWork, time.Now())
+		}
+	case "d":
+		// the item is done, which closes the pomodoro
+		t.stopPomo()
+		t.err = p.item.SetStatus(tuido.Checked)
+		t.repopulateKeepingSelection()
+	case "esc", "x", "q":
+		// abandon the cycle, keeping credit for time focused so far
+		t.stopPomo()
+	}
+	return nil
+}
+
+func (t tui) pomoView() string {
+	p := t.pomodoro
+	faint := lg.NewStyle().Faint(true)
+
+	rows := []string{"> " + lg.NewStyle().Bold(true).Render(t.renderTuido(*p.item, t.w/2)), ""}
+
+	if p.phase == pomoSetup {
+		rows = append(rows,
+			"minutes of focus: "+t.pomoEditor.View(),
+			"",
+			faint.Render("[enter]: start   [esc]: cancel"))
+	} else {
+		status := p.phase.String()
+		if p.paused {
+			status += " (paused)"
+		}
+		_, _, cycles := t.config.pomoDurations()
+
+		rows = append(rows,
+			lg.NewStyle().Bold(true).Render(status),
+			"",
+			lg.NewStyle().Bold(true).Render(formatClock(p.remaining(time.Now()))),
+			"",
+			fmt.Sprintf("pomodoros completed: %d (long break every %d)", p.cycles, cycles),
+			"",
+		)
+
+		keys := []string{"[space]: pause/resume", "[esc]: stop", "d: mark done"}
+		switch p.phase {
+		case pomoBreak, pomoLongBreak:
+			keys = append(keys, "s: skip break")
+		case pomoReady:
+			keys = []string{"[enter]: start next pomodoro", "[esc]: stop", "d: mark done"}
+		}
+		rows = append(rows, faint.Render(strings.Join(keys, "   ")))
+	}
+
+	if t.err != nil {
+		rows = append(rows, lg.NewStyle().Bold(true).Foreground(lg.Color("#ff2222")).Render(t.err.Error()))
+	}
+
+	return lg.NewStyle().
+		Align(lg.Left).
+		Margin(2).
+		Width(t.w / 2).
+		Render(lg.JoinVertical(lg.Left, rows...))
+}
+
+// formatClock formats a duration as mm:ss, rounding up to the second.
+func formatClock(d time.Duration) string {
+	seconds := int(math.Ceil(d.Seconds()))
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,237 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// refileScreen picks a destination for an item: first a file, via a
+// fuzzy filter over the scanned files, then a group within that file.
+type refileScreen struct {
+	item *tuido.Item
+
+	files     []string
+	matches   []string
+	selection int
+	filter    textinput.Model
+
+	// target is the chosen file, and groups are its [x]it! groups.
+	// While target is "", the file is still being picked.
+	target string
+	groups []tuido.Group
+}
+
+func (t *tui) setRefileMode() {
+	item := t.currentSelection()
+	if item == nil {
+		return
+	}
+
+	// scanned files, plus any created during this session
+	known := map[string]struct{}{}
+	for _, f := range t.files {
+		known[f] = struct{}{}
+	}
+	for _, i := range t.items {
+		known[i.File()] = struct{}{}
+	}
+	files := []string{}
+	for f := range known {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+
+	filter := textinput.New()
+	filter.Prompt = "move to: "
+	filter.Placeholder = "file name"
+	filter.Focus()
+
+	t.refile = refileScreen{
+		item:    item,
+		files:   files,
+		matches: files,
+		filter:  filter,
+	}
+	t.mode = refiling
+}
+
+func (t *tui) updateRefile(msg tea.Msg) tea.Cmd {
+	r := &t.refile
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+
+	options := len(r.matches)
+	if r.target != "" {
+		options = len(r.groups) + 1 // groups, plus end of file
+	}
+
+	switch key.String() {
+	case "esc":
+		if r.target != "" {
+			r.target = ""
+			r.selection = 0
+		} else {
+			t.mode = navigation
+		}
+		return nil
+	case "up":
+		r.selection = max(0, r.selection-1)
+		return nil
+	case "down":
+		r.selection = min(options-1, r.selection+1)
+		return nil
+	case "enter":
+		if r.target == "" {
+			if len(r.matches) == 0 {
+				return nil
+			}
+			r.target = r.matches[r.selection]
+			r.selection = 0
+			var err error
+			r.groups, err = tuido.Groups(r.target)
+			if err != nil && !os.IsNotExist(err) {
+				t.err = err
+			}
+			if len(r.groups) == 0 {
+				t.finishRefile(nil)
+			}
+		} else if r.selection == len(r.groups) {
+			t.finishRefile(nil)
+		} else {
+			t.finishRefile(&r.groups[r.selection])
+		}
+		return nil
+	}
+
+	if r.target != "" {
+		return nil
+	}
+
+	var cmd tea.Cmd
+	r.filter, cmd = r.filter.Update(msg)
+	r.matches = fuzzyFilter(r.filter.Value(), r.files)
+	r.selection = min(r.selection, max(0, len(r.matches)-1))
+	return cmd
+}
+
+func (t *tui) finishRefile(group *tuido.Group) {
+	item := t.refile.item
+	t.creditClockOn(item)
+	t.err = item.RefileTo(t.refile.target, group, t.items)
+	t.mode = navigation
+	t.populateRenderSelection()
+	t.selectItem(item)
+}
+
+func (t tui) refileView() string {
+	r := t.refile
+	s := lg.NewStyle().Margin(1, 2)
+
+	title := lg.NewStyle().Bold(true).Render("Move item")
+	item := t.renderTuido(*r.item, t.w-6)
+
+	rows := []string{}
+	var prompt string
+	if r.target == "" {
+		prompt = r.filter.View()
+		for i, f := range r.matches {
+			if i >= t.h-12 {
+				rows = append(rows, lg.NewStyle().Faint(true).Render(
+					fmt.Sprintf("  ... %d more", len(r.matches)-i)))
+				break
+			}
+			rows = append(rows, pickerRow(displayPath(f), i == r.selection))
+		}
+	} else {
+		prompt = "into group in " + displayPath(r.target) + ":"
+		for i, g := range r.groups {
+			rows = append(rows, pickerRow(g.String(), i == r.selection))
+		}
+		rows = append(rows, pickerRow("(end of file)", r.selection == len(r.groups)))
+	}
+
+	footer := lg.NewStyle().Faint(true).Render("[enter]: choose   [esc]: back")
+
+	return s.Render(lg.JoinVertical(lg.Left, title, item, "", prompt, strings.Join(rows, "\n"), "", footer))
+}
+
+func pickerRow(label string, selected bool) string {
+	if selected {
+		return lg.NewStyle().Bold(true).Render("> " + label)
+	}
+	return "  " + label
+}
+
+// displayPath shortens paths under the working directory to relative paths.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// fuzzyFilter returns the candidates that fuzzily match pattern, best first.
+func fuzzyFilter(pattern string, candidates []string) []string {
+	type scored struct {
+		s     string
+		score int
+	}
+
+	matches := []scored{}
+	for _, c := range candidates {
+		if score, ok := fuzzyScore(pattern, displayPath(c)); ok {
+			matches = append(matches, scored{c, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	ret := []string{}
+	for _, m := range matches {
+		ret = append(ret, m.s)
+	}
+	return ret
+}
+
+// fuzzyScore rates how well pattern matches s as a case-insensitive
+// subsequence, favouring consecutive runs and matches within the file name.
+// ok is false if pattern is not a subsequence of s.
+func fuzzyScore(pattern, s string) (score int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	r := []rune(strings.ToLower(s))
+	base := len(r) - len([]rune(filepath.Base(s)))
+
+	matched := 0
+	previous := -2
+	for i, c := range r {
+		if matched < len(p) && c == p[matched] {
+			score++
+			if previous == i-1 {
+				score += 2
+			}
+			if i >= base {
+				score++
+			}
+			previous = i
+			matched++
+		}
+	}
+
+	if matched < len(p) {
+		return 0, false
+	}
+	return score, true
+}
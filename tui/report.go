@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// reportFormats are the output formats of `tuido report`.
+var reportFormats = []string{"text", "md", "csv"}
+
+// ReportCommand runs `tuido report`, which compares the #estimate and
+// #spent tags of the items in the working directory, and returns the
+// process exit code.
+func ReportCommand(args []string) int {
+	flags := flag.NewFlagSet("tuido report", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: "+strings.Join(reportFormats, ", "))
+	by := flags.String("by", "", "comma separated breakdowns to report (default all): tag, project, file, week")
+	includeArchives := flags.Bool("archives", false, "include archived items")
+	flags.Parse(args)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	adoptConfigSettings(filepath.Join(wd, ".tuido"))
+
+	groupings, err := parseGroupings(*by)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	_, items := scan(wd, *includeArchives)
+	if err := writeReport(os.Stdout, *format, groupings, items, wd); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	return 0
+}
+
+// parseGroupings reads a comma separated list of report breakdowns,
+// defaulting to all of them.
+func parseGroupings(list string) ([]tuido.Grouping, error) {
+	if list == "" {
+		return tuido.Groupings, nil
+	}
+
+	groupings := []tuido.Grouping{}
+	for _, name := range strings.Split(list, ",") {
+		found := false
+		for _, g := range tuido.Groupings {
+			if string(g) == strings.TrimSpace(name) {
+				groupings = append(groupings, g)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown report breakdown: %s", name)
+		}
+	}
+	return groupings, nil
+}
+
+// writeReport writes the effort report for items to w, in the given
+// format. Files are named relative to wd.
+func writeReport(w io.Writer, format string, groupings []tuido.Grouping, items []*tuido.Item, wd string) error {
+	sections := map[tuido.Grouping][]tuido.EffortStats{}
+	for _, g := range groupings {
+		sections[g] = tuido.EffortReport(items, g)
+		if g == tuido.ByFile {
+			for i, s := range sections[g] {
+				if rel, err := filepath.Rel(wd, s.Group); err == nil && !strings.HasPrefix(rel, "..") {
+					sections[g][i].Group = rel
+				}
+			}
+		}
+	}
+	total := tuido.EffortTotal(items)
+
+	switch format {
+	case "text":
+		return writeTextReport(w, groupings, sections, total)
+	case "md":
+		return writeMarkdownReport(w, groupings, sections, total)
+	case "csv":
+		return writeCSVReport(w, groupings, sections, total)
+	}
+	return fmt.Errorf("unknown report format: %s (expected one of %s)", format, strings.Join(reportFormats, ", "))
+}
+
+var reportColumns = []string{"items", "estimate", "spent", "done", "accuracy", "overruns"}
+
+// reportCells formats a row of effort stats for display.
+func reportCells(s tuido.EffortStats) []string {
+	accuracy := "-"
+	if ratio, ok := s.Accuracy(); ok {
+		accuracy = fmt.Sprintf("%.2fx", ratio)
+	}
+	return []string{
+		fmt.Sprint(s.Items),
+		tuido.FormatEffort(s.Estimate),
+		tuido.FormatEffort(s.Spent),
+		fmt.Sprint(s.Finished),
+		accuracy,
+		fmt.Sprint(s.Overruns),
+	}
+}
+
+func writeTextReport(w io.Writer, groupings []tuido.Grouping, sections map[tuido.Grouping][]tuido.EffortStats, total tuido.EffortStats) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	row := func(group string, cells []string) {
+		fmt.Fprintf(tw, "%s\t%s\t\n", group, strings.Join(cells, "\t"))
+	}
+
+	for _, g := range groupings {
+		row("by "+string(g), reportColumns)
+		for _, s := range sections[g] {
+			row(s.Group, reportCells(s))
+		}
+		// an empty row of cells keeps the columns aligned across sections
+		fmt.Fprintln(tw, strings.Repeat("\t", len(reportColumns)+1))
+	}
+	row("total", reportCells(total))
+	return tw.Flush()
+}
+
+func writeMarkdownReport(w io.Writer, groupings []tuido.Grouping, sections map[tuido.Grouping][]tuido.EffortStats, total tuido.EffortStats) error {
+	header := "| " + strings.Join(reportColumns, " | ") + " |\n"
+	align := "|---" + strings.Repeat("|--:", len(reportColumns)) + "|\n"
+	row := func(group string, s tuido.EffortStats) string {
+		// pipes would split the cell
+		group = strings.ReplaceAll(group, "|", `\|`)
+		return "| " + group + " | " + strings.Join(reportCells(s), " | ") + " |\n"
+	}
+
+	out := "# Estimates vs time spent\n\n"
+	for _, g := range groupings {
+		out += "## By " + string(g) + "\n\n| " + string(g) + " " + header + align
+		for _, s := range sections[g] {
+			out += row(s.Group, s)
+		}
+		out += "\n"
+	}
+	out += "## Total\n\n| " + header + align + row("total", total)
+
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// writeCSVReport writes one row per group, with durations in minutes,
+// for use in spreadsheets.
+func writeCSVReport(w io.Writer, groupings []tuido.Grouping, sections map[tuido.Grouping][]tuido.EffortStats, total tuido.EffortStats) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"breakdown", "group", "items", "estimate_minutes", "spent_minutes",
+		"done", "done_estimate_minutes", "done_spent_minutes", "accuracy", "overruns"})
+
+	row := func(breakdown string, s tuido.EffortStats) {
+		accuracy := ""
+		if ratio, ok := s.Accuracy(); ok {
+			accuracy = fmt.Sprintf("%.3f", ratio)
+		}
+		cw.Write([]string{
+			breakdown, s.Group, fmt.Sprint(s.Items),
+			fmt.Sprintf("%.0f", s.Estimate.Minutes()), fmt.Sprintf("%.0f", s.Spent.Minutes()),
+			fmt.Sprint(s.Finished),
+			fmt.Sprintf("%.0f", s.FinishedEstimate.Minutes()), fmt.Sprintf("%.0f", s.FinishedSpent.Minutes()),
+			accuracy, fmt.Sprint(s.Overruns),
+		})
+	}
+
+	for _, g := range groupings {
+		for _, s := range sections[g] {
+			row(string(g), s)
+		}
+	}
+	row("total", total)
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// statsScreen shows the effort report in app, one breakdown at a time.
+type statsScreen struct {
+	grouping int
+	report   string
+}
+
+func (t *tui) setStatsMode() tea.Cmd {
+	t.mode = stats
+	t.refreshStats()
+	return nil
+}
+
+func (t *tui) refreshStats() {
+	wd, _ := os.Getwd()
+	var b strings.Builder
+	g := tuido.Groupings[t.stats.grouping]
+	if err := writeReport(&b, "text", []tuido.Grouping{g}, t.items, wd); err != nil {
+		t.err = err
+	}
+	t.stats.report = b.String()
+}
+
+func (t *tui) updateStats(msg tea.Msg) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return
+	}
+
+	switch key.String() {
+	case "tab", "right", "l":
+		t.stats.grouping = (t.stats.grouping + 1) % len(tuido.Groupings)
+		t.refreshStats()
+	case "shift+tab", "left", "h":
+		t.stats.grouping = (t.stats.grouping + len(tuido.Groupings) - 1) % len(tuido.Groupings)
+		t.refreshStats()
+	case "esc", "q", "S":
+		t.mode = navigation
+	}
+}
+
+func (t tui) statsView() string {
+	faint := lg.NewStyle().Faint(true)
+
+	tabs := []string{}
+	for i, g := range tuido.Groupings {
+		if i == t.stats.grouping {
+			tabs = append(tabs, lg.NewStyle().Bold(true).Underline(true).Render("by "+string(g)))
+		} else {
+			tabs = append(tabs, faint.Render("by "+string(g)))
+		}
+	}
+
+	// leave room for the heading and hints
+	report := strings.Split(strings.TrimRight(t.stats.report, "\n"), "\n")
+	if room := max(1, t.h-12); len(report) > room {
+		report = append(report[:room-1], faint.Render(fmt.Sprintf("... %d more rows; see `tuido report`", len(report)-room+1)))
+	}
+
+	return lg.NewStyle().Margin(1, 2).Render(lg.JoinVertical(lg.Left,
+		lg.NewStyle().Bold(true).Render("Estimates vs time spent"),
+		"",
+		strings.Join(tabs, "   "),
+		"",
+		strings.Join(report, "\n"),
+		"",
+		faint.Render("accuracy is time spent / estimated, for done items. overruns have spent more than their estimates."),
+		"",
+		faint.Render("[tab]: next breakdown   [esc]: back   `tuido report` prints this report as text, md, or csv"),
+	))
+}
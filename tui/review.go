@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// delegatedTag marks items that have been handed off to someone else
+// during a review.
+const delegatedTag = "delegated"
+
+// reviewStep is one category of items visited by the weekly review.
+type reviewStep struct {
+	title  string
+	prompt string
+	items  []*tuido.Item
+}
+
+// reviewScreen walks through the steps of a GTD style weekly review,
+// one item at a time.
+type reviewScreen struct {
+	steps []reviewStep
+	step  int
+	index int
+
+	// decided are the items already decided on in this review, which
+	// are skipped when they turn up again in a later step
+	decided map[*tuido.Item]bool
+}
+
+// newReview gathers the items for each review step. lastReview bounds
+// the "done since" step, which falls back to the past week for a first review.
+func newReview(items []*tuido.Item, lastReview, now time.Time) reviewScreen {
+	week := 7 * 24 * time.Hour
+	if lastReview.IsZero() {
+		lastReview = now.Add(-week)
+	}
+
+	steps := []reviewStep{
+		{title: "Stale ongoing items", prompt: "Still in progress? Recommit, defer, delegate, or drop."},
+		{title: "Overdue items", prompt: "These are past due. Do them, defer them, or let them go."},
+		{title: "Items without due dates", prompt: "Is each of these still worth doing?"},
+		{title: "Waking up this week", prompt: "Snoozed items returning over the next seven days."},
+		{title: "Done since the last review", prompt: "Take a moment to notice what got finished."},
+	}
+
+	for _, item := range items {
+		pending := item.Satus() == tuido.Open || item.Satus() == tuido.Ongoing
+		due := item.Due()
+		wakes := item.ActiveDate()
+
+		if item.Satus() == tuido.Ongoing {
+			if created := item.Created(); created == nil || created.Before(now.Add(-week)) {
+				steps[0].items = append(steps[0].items, item)
+			}
+		}
+		if pending && due != nil && due.Before(now) {
+			steps[1].items = append(steps[1].items, item)
+		}
+		if pending && item.Active() && due == nil {
+			steps[2].items = append(steps[2].items, item)
+		}
+		if pending && !item.Active() && wakes != nil && wakes.Before(now.Add(week)) {
+			steps[3].items = append(steps[3].items, item)
+		}
+		if completed := item.Completed(); item.Satus() == tuido.Checked &&
+			completed != nil && !completed.Before(lastReview) {
+			steps[4].items = append(steps[4].items, item)
+		}
+	}
+
+	return reviewScreen{steps: steps, decided: map[*tuido.Item]bool{}}
+}
+
+func (r *reviewScreen) current() *tuido.Item {
+	if r.step >= len(r.steps) || r.index >= len(r.steps[r.step].items) {
+		return nil
+	}
+	return r.steps[r.step].items[r.index]
+}
+
+// advance moves to the next undecided item, skipping over empty steps.
+// It returns false once every step has been visited.
+func (r *reviewScreen) advance() bool {
+	r.index++
+	for r.step < len(r.steps) {
+		if r.index >= len(r.steps[r.step].items) {
+			r.step++
+			r.index = 0
+			continue
+		}
+		if !r.decided[r.steps[r.step].items[r.index]] {
+			return true
+		}
+		r.index++
+	}
+	return false
+}
+
+// skipEmptySteps moves past steps with no items, for starting or resuming.
+func (r *reviewScreen) skipEmptySteps() bool {
+	r.index--
+	return r.advance()
+}
+
+func (t *tui) setReviewMode() {
+	t.review = newReview(t.items, t.state.lastReview, time.Now())
+	if t.state.reviewStep < len(t.review.steps) {
+		t.review.step = t.state.reviewStep
+	}
+
+	if !t.review.skipEmptySteps() {
+		t.finishReview()
+		return
+	}
+	t.mode = reviewing
+}
+
+func (t *tui) finishReview() {
+	t.state.lastReview = time.Now()
+	t.state.reviewStep = 0
+	t.notifs = append(t.notifs, fmt.Sprintf("Weekly review completed %s", t.state.lastReview.Format("Mon Jan 2 15:04")))
+	t.mode = navigation
+	t.repopulateKeepingSelection()
+}
+
+// updateReview applies the one-key decisions of the review to the current item.
+func (t *tui) updateReview(msg tea.Msg) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return
+	}
+
+	item := t.review.current()
+	t.err = nil
+
+	switch key.String() {
+	case "esc", "q":
+		// resume from this step next time
+		t.state.reviewStep = t.review.step
+		t.mode = navigation
+		t.repopulateKeepingSelection()
+		return
+	case "d": // do: recommit to it as a next action
+		if !item.Active() {
+			t.err = item.Unsnooze()
+		}
+		if t.err == nil {
+			t.err = item.SetStatus(tuido.Ongoing)
+		}
+	case "f": // defer
+		t.snoozeItem(item)
+	case "g": // delegate
+		t.err = item.SetTag(delegatedTag, "")
+	case "s": // drop
+		t.err = item.SetStatus(tuido.Obsolete)
+	case " ", "enter", "right", "l": // keep as-is
+	default:
+		return
+	}
+
+	if t.err != nil {
+		return
+	}
+
+	t.review.decided[item] = true
+	if !t.review.advance() {
+		t.finishReview()
+	}
+}
+
+func (t tui) reviewView() string {
+	r := t.review
+	step := r.steps[r.step]
+	s := lg.NewStyle().Margin(1, 2)
+
+	title := lg.NewStyle().Bold(true).Render(
+		fmt.Sprintf("Weekly review - step %d of %d: %s", r.step+1, len(r.steps), step.title))
+	progress := lg.NewStyle().Faint(true).Render(
+		fmt.Sprintf("item %d of %d. %s", r.index+1, len(step.items), step.prompt))
+
+	item := r.current()
+	body := lg.JoinVertical(lg.Left,
+		t.renderTuido(*item, t.w-6),
+		lg.NewStyle().Faint(true).Render(item.Location()),
+	)
+
+	keys := []string{"d: do (mark ongoing)", "f: defer (snooze)", "g: delegate (#" + delegatedTag + ")",
+		"s: drop (obsolete)", "[space]: keep as-is", "esc: pause review"}
+	footer := lg.NewStyle().Faint(true).Render(strings.Join(keys, "   "))
+
+	if t.err != nil {
+		footer = lg.JoinVertical(lg.Left, footer,
+			lg.NewStyle().Bold(true).Foreground(lg.Color("#ff2222")).Render(t.err.Error()))
+	}
+
+	return s.Render(lg.JoinVertical(lg.Left, title, progress, "", body, "", footer))
+}
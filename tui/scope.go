@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/nilock/tuido/tuido"
+)
+
+// scope narrows the listed items to a single GTD style +project or
+// @context. The empty scope lists everything.
+type scope string
+
+func projectScope(name string) scope { return scope("+" + name) }
+func contextScope(name string) scope { return scope("@" + name) }
+
+func (s scope) matches(item *tuido.Item) bool {
+	if s == "" {
+		return true
+	}
+
+	names := item.Contexts()
+	if strings.HasPrefix(string(s), "+") {
+		names = item.Projects()
+	}
+
+	for _, name := range names {
+		if name == string(s[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeSummary tallies the progress of the items in a scope.
+type scopeSummary struct {
+	scope   scope
+	pending int
+	done    int
+}
+
+func (s scopeSummary) String() string {
+	total := s.pending + s.done
+	if total == 0 {
+		return string(s.scope)
+	}
+	return fmt.Sprintf("%s %d/%d", s.scope, s.done, total)
+}
+
+// progressBar renders the fraction of done items as a bar of the given width.
+func (s scopeSummary) progressBar(width int) string {
+	total := s.pending + s.done
+	filled := 0
+	if total > 0 {
+		filled = s.done * width / total
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+func summarize(s scope, items []*tuido.Item) scopeSummary {
+	summary := scopeSummary{scope: s}
+	for _, item := range items {
+		if !s.matches(item) {
+			continue
+		}
+		switch item.Satus() {
+		case tuido.Open, tuido.Ongoing:
+			summary.pending++
+		case tuido.Checked, tuido.Obsolete:
+			summary.done++
+		}
+	}
+	return summary
+}
+
+// summarizeScopes returns progress summaries for every project and
+// context found among items - projects first, each group sorted by name.
+func summarizeScopes(items []*tuido.Item) []scopeSummary {
+	projects := map[scope]struct{}{}
+	contexts := map[scope]struct{}{}
+
+	for _, item := range items {
+		for _, p := range item.Projects() {
+			projects[projectScope(p)] = struct{}{}
+		}
+		for _, c := range item.Contexts() {
+			contexts[contextScope(c)] = struct{}{}
+		}
+	}
+
+	summaries := []scopeSummary{}
+	for _, group := range []map[scope]struct{}{projects, contexts} {
+		names := []scope{}
+		for s := range group {
+			names = append(names, s)
+		}
+		sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+		for _, s := range names {
+			summaries = append(summaries, summarize(s, items))
+		}
+	}
+
+	return summaries
+}
+
+// scopeScreen is a picker over all projects and contexts, showing the
+// progress of each.
+type scopeScreen struct {
+	// summaries are the pickable scopes. The first entry is always
+	// the empty scope, which lists all items.
+	summaries []scopeSummary
+	selection int
+}
+
+func newScopeScreen(items []*tuido.Item, current scope) scopeScreen {
+	s := scopeScreen{
+		summaries: append([]scopeSummary{{}}, summarizeScopes(items)...),
+	}
+	for i, summary := range s.summaries {
+		if summary.scope == current {
+			s.selection = i
+		}
+	}
+	return s
+}
+
+func (s *scopeScreen) selected() scope {
+	return s.summaries[s.selection].scope
+}
+
+func (s *scopeScreen) View() string {
+	st := lg.NewStyle().Margin(2)
+
+	nameWidth := 0
+	for _, summary := range s.summaries {
+		nameWidth = max(nameWidth, lg.Width(string(summary.scope)))
+	}
+
+	rows := []string{}
+	for i, summary := range s.summaries {
+		cursor := "  "
+		if i == s.selection {
+			cursor = "> "
+		}
+
+		row := cursor + "all items"
+		if summary.scope != "" {
+			row = fmt.Sprintf("%s%-*s  %s  %d/%d done", cursor, nameWidth, summary.scope,
+				summary.progressBar(20), summary.done, summary.done+summary.pending)
+		}
+		if i == s.selection {
+			row = lg.NewStyle().Bold(true).Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(s.summaries) == 1 {
+		rows = append(rows, lg.NewStyle().Faint(true).
+			Render("\n  no +projects or @contexts found"))
+	}
+
+	footer := st.Copy().Faint(true).Render("enter: show items in scope, esc: back to item navigation")
+
+	return lg.JoinVertical(lg.Left, st.Render(strings.Join(rows, "\n")), footer)
+}
+
+// Update moves the picker's cursor. It returns the mode to switch to,
+// and whether a scope was chosen.
+func (s *scopeScreen) Update(msg tea.Msg) (mode, bool) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			return navigation, false
+		case "enter":
+			return navigation, true
+		case "up", "k":
+			s.selection = max(0, s.selection-1)
+		case "down", "j":
+			s.selection = min(len(s.summaries)-1, s.selection+1)
+		}
+	}
+	return scoping, false
+}
+
+func (t *tui) setScopeMode() {
+	t.scopes = newScopeScreen(t.items, t.scope)
+	t.mode = scoping
+}
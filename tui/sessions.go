@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// session is a record of one pomodoro focus period.
+type session struct {
+	// item is the location of the item focused on, and text its text at the time
+	item string
+	text string
+
+	start, end time.Time
+	// planned is the length of the focus period, and actual the time
+	// spent focused, less any pauses
+	planned, actual time.Duration
+}
+
+// sessionsPath is the location of the append-only pomodoro session log.
+// It is set in `init()`, alongside the tuido directory.
+var sessionsPath string
+
+// String formats the session as a tab separated log line.
+func (s session) String() string {
+	return strings.Join([]string{
+		s.start.Format(time.RFC3339),
+		s.end.Format(time.RFC3339),
+		s.planned.String(),
+		s.actual.Round(time.Second).String(),
+		s.item,
+		s.text,
+	}, "\t")
+}
+
+// appendSession adds s to the end of the session log at path.
+func appendSession(path string, s session) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(s.String() + "\n")
+	return err
+}
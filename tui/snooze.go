@@ -0,0 +1,73 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nilock/tuido/tuido"
+)
+
+// snoozePolicy returns the configured snooze policy for item: the policy
+// for the first of its tags that has one configured, or else the global policy.
+func (t *tui) snoozePolicy(item *tuido.Item) (tuido.SnoozePolicy, error) {
+	for _, tag := range item.Tags() {
+		if policy, ok := t.config.tagSnooze[tag.Name()]; ok {
+			return tuido.ParseSnoozePolicy(policy)
+		}
+	}
+
+	if t.config.snooze == "" {
+		return tuido.DefaultSnoozePolicy, nil
+	}
+	return tuido.ParseSnoozePolicy(t.config.snooze)
+}
+
+// snooze hides the current selection according to its snooze policy.
+func (t *tui) snooze() {
+	t.snoozeItem(t.currentSelection())
+}
+
+// snoozeItem hides item according to its snooze policy.
+func (t *tui) snoozeItem(item *tuido.Item) {
+	if item == nil {
+		return
+	}
+
+	policy, err := t.snoozePolicy(item)
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.err = item.SnoozeWith(policy)
+}
+
+func (t *tui) setSnoozeMode() tea.Cmd {
+	if t.currentSelection() != nil {
+		t.mode = snoozing
+		t.snoozeEditor.SetValue("")
+		t.snoozeEditor.Focus()
+	}
+	return nil
+}
+
+// updateSnoozing handles keypresses in the snooze prompt, which asks
+// for a specific duration or date to snooze the current selection until.
+func (t *tui) updateSnoozing(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			t.mode = navigation
+			return nil
+		case "enter":
+			if txt := t.snoozeEditor.Value(); txt != "" {
+				t.err = t.currentSelection().SnoozeFor(txt)
+				if t.err == nil {
+					t.mode = navigation
+				}
+			}
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	t.snoozeEditor, cmd = t.snoozeEditor.Update(msg)
+	return cmd
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appState is bookkeeping that persists between sessions. It is
+// stored in the tuido directory as `flag=value` lines, like config.
+type appState struct {
+	// lastSession is the time that tuido was last closed.
+	lastSession time.Time
+
+	// lastReview is the time that the last weekly review was completed.
+	lastReview time.Time
+	// reviewStep is the step of an unfinished weekly review, where
+	// the next review resumes.
+	reviewStep int
+
+	// rolledOver is the time that the leftovers of earlier days' plans
+	// were last dealt with.
+	rolledOver time.Time
+
+	// clockStart is when the stopwatch that was running when tuido closed
+	// was started, and clockItem and clockText the location and text of
+	// its item.
+	clockStart time.Time
+	clockItem  string
+	clockText  string
+}
+
+// statePath is the location of the persisted appState. It is set
+// in `init()`, alongside the tuido directory.
+var statePath string
+
+func loadState(path string) appState {
+	st := appState{}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return st
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		flag, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+
+		switch flag {
+		case "lastSession":
+			st.lastSession, _ = time.Parse(time.RFC3339, value)
+		case "lastReview":
+			st.lastReview, _ = time.Parse(time.RFC3339, value)
+		case "reviewStep":
+			st.reviewStep, _ = strconv.Atoi(value)
+		case "rolledOver":
+			st.rolledOver, _ = time.Parse(time.RFC3339, value)
+		case "clockStart":
+			st.clockStart, _ = time.Parse(time.RFC3339, value)
+		case "clockItem":
+			st.clockItem = value
+		case "clockText":
+			st.clockText = value
+		}
+	}
+
+	return st
+}
+
+func (st appState) String() string {
+	ret := fmt.Sprintf("lastSession=%s\n", st.lastSession.Format(time.RFC3339))
+	if !st.lastReview.IsZero() {
+		ret += fmt.Sprintf("lastReview=%s\n", st.lastReview.Format(time.RFC3339))
+	}
+	if st.reviewStep != 0 {
+		ret += fmt.Sprintf("reviewStep=%d\n", st.reviewStep)
+	}
+	if !st.rolledOver.IsZero() {
+		ret += fmt.Sprintf("rolledOver=%s\n", st.rolledOver.Format(time.RFC3339))
+	}
+	if !st.clockStart.IsZero() {
+		ret += fmt.Sprintf("clockStart=%s\nclockItem=%s\nclockText=%s\n",
+			st.clockStart.Format(time.RFC3339), st.clockItem, st.clockText)
+	}
+	return ret
+}
+
+func (st appState) save(path string) error {
+	return os.WriteFile(path, []byte(st.String()), 0666)
+}
@@ -2,13 +2,13 @@ package tui
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,7 +21,13 @@ import (
 	walkrepo "github.com/nilock/walk-repo"
 )
 
-func Run() {
+// Run launches the app. args are the command line arguments, less
+// the program name.
+func Run(args []string) {
+	flags := flag.NewFlagSet("tuido", flag.ExitOnError)
+	includeArchives := flags.Bool("archives", false, "include archived items when browsing done items")
+	flags.Parse(args)
+
 	wrkdirStr, err := os.Getwd() // [ ] only from cli flag? YES! or... follow .gitignore
 
 	if err != nil {
@@ -31,6 +37,52 @@ func Run() {
 	adoptConfigSettings(filepath.Join(wrkdirStr, ".tuido"))
 	// [ ] read cli flags for added extensions / extension specificity
 
+	files, items := scan(wrkdirStr, *includeArchives)
+
+	model := newTUI(items, runConfig)
+	model.files = files
+	model.includeArchives = *includeArchives
+	model.state = loadState(statePath)
+	// the clock resumes first, so that its item's time is credited if
+	// housekeeping archives it
+	model.resumeClock()
+	model.houseKeeping()
+
+	// the program quits to hand the terminal to an external editor, and
+	// is restarted once the editor exits.
+	for {
+		// ticks scheduled by an earlier program are lost with it
+		model.ticking = model.clock != nil
+		prog := tea.NewProgram(model, tea.WithAltScreen())
+
+		final, err := prog.StartReturningModel()
+		if err != nil {
+			panic(err)
+		}
+		model = final.(tui)
+
+		if model.editing == nil {
+			break
+		}
+		req := *model.editing
+		model.editing = nil
+		model.err = runEditor(req)
+		model.reloadFile(req)
+	}
+
+	st := model.state
+	st.lastSession = time.Now()
+	model.clock.save(&st)
+	if err := st.save(statePath); err != nil {
+		fmt.Printf("error saving app state: %s\n", err)
+	}
+}
+
+// scan finds the files to parse in the working directory and the configured
+// writeto location, and returns them along with their sorted items.
+//
+// Archive files are skipped unless includeArchives.
+func scan(wrkdirStr string, includeArchives bool) ([]string, []*tuido.Item) {
 	files := make(map[string]struct{})
 
 	wtStat, err := os.Stat(runConfig.writeto)
@@ -53,30 +105,41 @@ func Run() {
 		}
 	}
 
+	for f := range files {
+		if runConfig.isArchiveFile(f) {
+			delete(files, f)
+		}
+	}
+	if includeArchives {
+		for _, f := range runConfig.archiveFiles() {
+			files[f] = struct{}{}
+		}
+	}
+
+	fileList := []string{}
 	items := []*tuido.Item{}
 	for f := range files {
+		fileList = append(fileList, f)
 		items = append(items, getItems(f)...)
 	}
 
 	sortItems(items)
 
-	tui := newTUI(items, runConfig)
-	tui.houseKeeping()
-
-	prog := tea.NewProgram(tui, tea.WithAltScreen())
-
-	if err := prog.Start(); err != nil {
-		panic(err)
-	}
+	return fileList, items
 }
 
 type itemType string
 
 const (
-	todo itemType = "todo"
-	done itemType = "done"
+	todo    itemType = "todo"
+	today   itemType = "today"
+	done    itemType = "done"
+	snoozed itemType = "snoozed"
 )
 
+// itemTypes are the list views, in the order that they are cycled through by [tab].
+var itemTypes = []itemType{todo, today, done, snoozed}
+
 func newTUI(items []*tuido.Item, cfg config) tui {
 	// the search bar:
 	filter := textinput.New()
@@ -85,9 +148,19 @@ func newTUI(items []*tuido.Item, cfg config) tui {
 	itemEditor := textinput.New()
 	itemEditor.Prompt = ">>>"
 
+	snoozeEditor := textinput.New()
+	snoozeEditor.Prompt = "snooze until: "
+	snoozeEditor.Placeholder = "3d, 4h, friday, tomorrow 9am"
+
+	sinks, err := notifiers(cfg.notify)
+	policy, policyErr := parseNagPolicy(cfg)
+	if err == nil {
+		err = policyErr
+	}
+
 	return tui{
 		config:          cfg,
-		err:             nil,
+		err:             err,
 		notifs:          []string{},
 		items:           items,
 		renderSelection: nil,
@@ -97,7 +170,13 @@ func newTUI(items []*tuido.Item, cfg config) tui {
 		pomoEditor:      textinput.New(),
 		filter:          filter,
 		itemEditor:      itemEditor,
+		snoozeEditor:    snoozeEditor,
 		tagColors:       populateTagColorStyles(items),
+		showDetail:      cfg.detail != "",
+		blames:          map[string]*fileBlame{},
+		notifiers:       sinks,
+		nagPolicy:       policy,
+		dueChecked:      time.Now(),
 		h:               0,
 		w:               0,
 	}
@@ -113,6 +192,37 @@ func (t *tui) houseKeeping() {
 				curent, local, utils.ReleaseURL))
 	}
 
+	if woken := t.wokenSinceLastSession(); len(woken) > 0 {
+		notif := fmt.Sprintf("%d snoozed item(s) woke up since your last session:", len(woken))
+		for _, item := range woken {
+			notif += "\n  " + item.String()
+		}
+		t.notifs = append(t.notifs, notif)
+	}
+
+	t.autoArchive()
+	t.rollOver()
+}
+
+// wokenSinceLastSession returns the pending items whose #active time
+// passed while tuido was closed.
+func (t *tui) wokenSinceLastSession() []*tuido.Item {
+	woken := []*tuido.Item{}
+	if t.state.lastSession.IsZero() {
+		return woken
+	}
+
+	now := time.Now()
+	for _, item := range t.items {
+		if item.Satus() != tuido.Open && item.Satus() != tuido.Ongoing {
+			continue
+		}
+		if wakes := item.ActiveDate(); wakes != nil &&
+			wakes.After(t.state.lastSession) && !wakes.After(now) {
+			woken = append(woken, item)
+		}
+	}
+	return woken
 }
 
 // populateTagColorStyles returns a coloring style for
@@ -120,9 +230,18 @@ func (t *tui) houseKeeping() {
 func populateTagColorStyles(items []*tuido.Item) map[string]lg.Style {
 	// [ ] this should be recalculated / shifted when new tags are added
 	// [ ] audit: results in UI suggest a bug. Colors seem clustered. ##active=2022-05-26 ##zzz=2 #active=2022-05-25 #zzz=1
-	var tags []tuido.Tag
+	// +projects and @contexts are colored alongside tags, keyed with their sigils
+	var tags []string
 	for _, item := range items {
-		tags = append(tags, item.Tags()...)
+		for _, tag := range item.Tags() {
+			tags = append(tags, tag.Name())
+		}
+		for _, p := range item.Projects() {
+			tags = append(tags, string(projectScope(p)))
+		}
+		for _, c := range item.Contexts() {
+			tags = append(tags, string(contextScope(c)))
+		}
 	}
 
 	tagColors := map[string]lg.Style{}
@@ -131,7 +250,7 @@ func populateTagColorStyles(items []*tuido.Item) map[string]lg.Style {
 
 	for i, tag := range tags {
 		hue := int(offset+float64(i)*interval) % 360
-		tagColors[tag.Name()] = lg.NewStyle().
+		tagColors[tag] = lg.NewStyle().
 			Foreground(
 				lg.Color(
 					colorful.Hcl(float64(hue), .9, 0.85).Clamped().Hex(),
@@ -151,6 +270,15 @@ const (
 	pomo
 	nag
 	peek
+	snoozing
+	scoping
+	reviewing
+	processing
+	refiling
+	deleting
+	stats
+	focus
+	planning
 )
 
 type tui struct {
@@ -159,8 +287,15 @@ type tui struct {
 
 	notifs []string
 
+	// files are the files scanned for items
+	files []string
+	// includeArchives is set when archive files were scanned for items
+	includeArchives bool
+
 	items       []*tuido.Item
 	itemsFilter itemType
+	// scope narrows the listed items to a +project or @context
+	scope scope
 
 	renderSelection []*tuido.Item
 	selection       int
@@ -171,18 +306,60 @@ type tui struct {
 
 	filter     textinput.Model
 	itemEditor textinput.Model
+	// completer suggests #tags while editing
+	completer tagCompleter
+	// descEditor edits the selected item's description while editing
+	descEditor descriptionEditor
+	// expanded is set while items' descriptions are shown in the list
+	expanded bool
+
+	// snoozeEditor prompts for a specific snooze duration or date
+	snoozeEditor textinput.Model
 
 	// pomoEditor is the textinput.Model for the pomo clock
 	pomoEditor textinput.Model
-	// pomoTimer is the ticker that decrements the pomo clock
-	pomoTimer time.Ticker
-	// pomoTimeRemaining is the time remaining in seconds
-	pomoTimeRemaining int
-	// pomoTimeSet is the original time set by the user
-	pomoTimeSet int
-
-	nag  nagScreen
-	peek peekScreen
+	// pomodoro is the running pomodoro cycle
+	pomodoro pomodoro
+	// clock is the running stopwatch, if any
+	clock *stopwatch
+	// ticking is set while a tick is scheduled to update the clocks
+	ticking bool
+
+	// notifiers deliver notifications of timer events
+	notifiers []notifier
+	// dueChecked is when items were last checked for coming due
+	dueChecked time.Time
+
+	// nagPolicy decides when creating items is challenged
+	nagPolicy nagPolicy
+	// override is set while an item created by overcoming a nag is
+	// being written, to be logged once it is saved
+	override *override
+
+	nag    nagScreen
+	peek   peekScreen
+	scopes scopeScreen
+	review reviewScreen
+	inbox  inboxScreen
+	refile refileScreen
+	stats  statsScreen
+	focus  focusScreen
+	plan   planScreen
+
+	// showDetail is set while the detail pane is shown beside the list
+	showDetail bool
+	// blames caches git blame descriptions of item lines by file, for the
+	// detail pane
+	blames map[string]*fileBlame
+
+	// editing is set when the program quits to open an item in an external editor
+	editing *editorRequest
+
+	// deletions are this session's deleted items, most recent last, for undo
+	deletions []*tuido.Deletion
+
+	// state is persisted between sessions
+	state appState
 
 	tagColors map[string]lg.Style
 
@@ -199,6 +376,8 @@ func (t *tui) setSelection(s int) {
 	t.selection = s
 }
 
+// tickMsg updates the pomodoro and stopwatch clocks. Ticks are only
+// scheduled while a clock is running.
 type tickMsg time.Time
 
 func tick() tea.Cmd {
@@ -207,61 +386,35 @@ func tick() tea.Cmd {
 	})
 }
 
-func (t *tui) setPomoMode() tea.Cmd {
-	t.mode = pomo
-
-	t.pomoEditor.Focus()
-	t.pomoEditor.SetValue("")
-	t.pomoTimer.Stop()
-
-	return nil
-}
-
-func (t *tui) startPomo() {
-	if t.pomoEditor.Value() == "" {
-		return
-	}
-
-	var err error
-	setTime, err := strconv.ParseFloat(t.pomoEditor.Value(), 64)
-	t.pomoTimeSet = int(setTime * 60)
-	t.pomoTimeRemaining = t.pomoTimeSet
-
-	if err != nil {
-		t.err = err
-		fmt.Println(err)
+// keepTicking schedules the next tick while a pomodoro, stopwatch, or
+// nag cooldown is running, unless one is already scheduled.
+func (t *tui) keepTicking() tea.Cmd {
+	running := (t.mode == pomo && t.pomodoro.timed()) || t.clock != nil ||
+		(t.mode == nag && t.nag.cooling(time.Now()))
+	if t.ticking || !running {
+		return nil
 	}
+	t.ticking = true
+	return tick()
 }
 
-func (t *tui) setPeekMode() tea.Cmd {
-	t.mode = peek
-
-	if t.currentSelection() != nil {
-		t.peek = peekScreen{*t.currentSelection()}
-	} else if len(t.items) != 0 {
-		t.peek = peekScreen{*t.items[0]}
-	}
-
-	return nil
-}
+// refreshMsg periodically refreshes the item list, so that snoozed
+// items wake up without a keypress.
+type refreshMsg time.Time
 
-func (t *tui) setEditMode() tea.Cmd {
-	if t.currentSelection() != nil {
-		t.mode = edit
-		t.itemEditor.SetValue(t.currentSelection().Text())
-		t.itemEditor.CursorEnd()
-		t.itemEditor.Focus()
-	}
-	return nil
+func refresh() tea.Cmd {
+	return tea.Tick(time.Minute, func(t time.Time) tea.Msg {
+		return refreshMsg(t)
+	})
 }
 
-// tab cycles the view between todos and dones.
+// tab cycles the view between todos, today's plan, dones, and snoozed items.
 func (t *tui) tab() {
-
-	if t.itemsFilter == todo {
-		t.itemsFilter = done
-	} else if t.itemsFilter == done {
-		t.itemsFilter = todo
+	for i, it := range itemTypes {
+		if t.itemsFilter == it {
+			t.itemsFilter = itemTypes[(i+1)%len(itemTypes)]
+			break
+		}
 	}
 
 	t.populateRenderSelection()
@@ -291,6 +444,15 @@ func (t *tui) populateRenderSelection() {
 		}
 	}
 
+	if t.itemsFilter == today {
+		now := time.Now()
+		for _, i := range t.items {
+			if i.PlannedFor(now) && i.Satus() != tuido.Obsolete {
+				t.renderSelection = append(t.renderSelection, i)
+			}
+		}
+	}
+
 	if t.itemsFilter == done {
 		for _, i := range t.items {
 			if i.Satus() == tuido.Checked || i.Satus() == tuido.Obsolete {
@@ -299,12 +461,59 @@ func (t *tui) populateRenderSelection() {
 		}
 	}
 
+	if t.itemsFilter == snoozed {
+		for _, i := range t.items {
+			if (i.Satus() == tuido.Ongoing || i.Satus() == tuido.Open) &&
+				!i.Active() {
+				t.renderSelection = append(t.renderSelection, i)
+			}
+		}
+	}
+
+	t.applyScope()
 	t.applyFilter()
-	sortItems(t.renderSelection)
+	if t.itemsFilter == snoozed {
+		sortByWakeTime(t.renderSelection)
+	} else {
+		sortItems(t.renderSelection)
+	}
 	// ensure the previous selection value is still in range
 	t.setSelection(t.selection)
 }
 
+// repopulateKeepingSelection refreshes the renderSelection while
+// keeping the cursor on the currently selected item, if it is
+// still listed.
+func (t *tui) repopulateKeepingSelection() {
+	current := t.currentSelection()
+	t.populateRenderSelection()
+	t.selectItem(current)
+}
+
+// selectItem moves the cursor to item, if it is listed.
+func (t *tui) selectItem(item *tuido.Item) {
+	for i, listed := range t.renderSelection {
+		if listed == item {
+			t.setSelection(i)
+			return
+		}
+	}
+}
+
+func (t *tui) applyScope() {
+	if t.scope == "" {
+		return
+	}
+
+	scoped := []*tuido.Item{}
+	for _, item := range t.renderSelection {
+		if t.scope.matches(item) {
+			scoped = append(scoped, item)
+		}
+	}
+	t.renderSelection = scoped
+}
+
 func (t *tui) applyFilter() {
 	filter := t.filter.Value()
 	if len(filter) != 0 {
@@ -330,7 +539,14 @@ func (t *tui) applyFilter() {
 	}
 }
 
-func (t tui) Init() tea.Cmd { return tick() }
+func (t tui) Init() tea.Cmd {
+	t.populateRenderSelection()
+	if t.ticking {
+		// a stopwatch is already running
+		return tea.Batch(refresh(), tick(), t.blameSelection())
+	}
+	return tea.Batch(refresh(), t.blameSelection())
+}
 
 func getItems(file string) []*tuido.Item {
 	items := []*tuido.Item{}
@@ -408,3 +624,16 @@ func sortItems(items []*tuido.Item) {
 		}
 	})
 }
+
+// sortByWakeTime orders snoozed items by the time they become active.
+func sortByWakeTime(items []*tuido.Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		x := items[i].ActiveDate()
+		y := items[j].ActiveDate()
+
+		if x == nil || y == nil {
+			return x != nil
+		}
+		return x.Before(*y)
+	})
+}
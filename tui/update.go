@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nilock/tuido/tuido"
@@ -9,29 +10,71 @@ import (
 
 func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// [ ] refactor as separate methods per mode
-	if _, ok := msg.(tickMsg); ok {
-		t.pomoTimeRemaining--
-		if t.pomoTimeRemaining == 1 {
-			// pomo is done. Increment time spent:
-			t.currentSelection().IncrementTimeSpent(t.pomoTimeSet)
-			// ...  & switch to nav mode
-			t.mode = navigation
-		}
-		if t.pomoTimeRemaining < 0 {
-			t.pomoTimeRemaining = 0
+	if msg, ok := msg.(tickMsg); ok {
+		t.ticking = false
+		cmd := t.tickPomo(time.Time(msg))
+		return t, tea.Batch(cmd, t.keepTicking())
+	}
+
+	if msg, ok := msg.(refreshMsg); ok {
+		if t.mode == navigation && !t.filter.Focused() {
+			// snoozed items wake up as their #active time passes
+			t.repopulateKeepingSelection()
 		}
-		return t, tick()
+		return t, tea.Batch(t.notifyDue(time.Time(msg)), refresh(), t.blameSelection())
+	}
+
+	// every mode is sized to the window, including one opened at startup
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		t.h = msg.Height
+		t.w = msg.Width
+		return t, nil
+	}
+
+	if msg, ok := msg.(notifyErrMsg); ok {
+		t.err = msg.err
+		return t, nil
+	}
+
+	if msg, ok := msg.(blameMsg); ok {
+		t.storeBlame(msg)
+		return t, nil
 	}
 
 	if t.mode == nag {
-		mode, complete := t.nag.Update(msg)
+		mode, complete, cmd := t.nag.Update(msg)
+		t.mode = mode
+		if t.mode == navigation && complete {
+			t.overrideNag()
+		}
+		return t, cmd
+	}
+
+	if t.mode == scoping {
+		mode, chosen := t.scopes.Update(msg)
 		t.mode = mode
-		if t.mode == navigation && complete { // [ ] need to switch on some nag content?
-			t.createNewItem()
+		if chosen {
+			t.scope = t.scopes.selected()
+			t.populateRenderSelection()
 		}
 		return t, nil
 	}
 
+	if t.mode == reviewing {
+		t.updateReview(msg)
+		return t, nil
+	}
+
+	if t.mode == processing {
+		cmd := t.updateInbox(msg)
+		return t, cmd
+	}
+
+	if t.mode == refiling {
+		cmd := t.updateRefile(msg)
+		return t, cmd
+	}
+
 	if t.mode == help {
 		if _, ok := msg.(tea.KeyMsg); ok {
 			t.mode = navigation
@@ -40,84 +83,42 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if t.mode == peek {
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			key := msg.Type
-
-			if key == tea.KeyEsc ||
-				key == tea.KeyEnter {
-				t.mode = navigation
-				return t, nil
-			}
+		cmd := t.updatePeek(msg)
+		return t, cmd
+	}
 
-			if key == tea.KeyUp {
-				// scroll up
-			}
+	if t.mode == pomo {
+		cmd := t.updatePomo(msg)
+		return t, cmd
+	}
 
-			if key == tea.KeyDown {
-				// scroll down
-			}
-		}
-		return t, nil
+	if t.mode == focus {
+		cmd := t.updateFocus(msg)
+		return t, cmd
 	}
 
-	if t.mode == pomo {
-		if t.pomoTimeRemaining > 0 {
-			return t, nil // no msg processing other than the timer during a running clock
-		}
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			str := msg.String()
-			if str == "1" ||
-				str == "2" ||
-				str == "3" ||
-				str == "4" ||
-				str == "5" ||
-				str == "6" ||
-				str == "7" ||
-				str == "8" ||
-				str == "9" ||
-				str == "0" ||
-				str == "." ||
-				str == "left" ||
-				str == "right" ||
-				str == "delete" ||
-				str == "backspace" {
-				var cmd tea.Cmd
-				t.pomoEditor, cmd = t.pomoEditor.Update(msg)
+	if t.mode == stats {
+		t.updateStats(msg)
+		return t, nil
+	}
 
-				return t, cmd
-			}
-			if str == "enter" {
-				t.startPomo()
-			}
-			if str == "esc" {
-				t.pomoEditor.Reset()
-				t.mode = navigation
-			}
-		}
+	if t.mode == planning {
+		t.updatePlan(msg)
 		return t, nil
 	}
 
-	if t.mode == edit {
-		if msg, ok := msg.(tea.KeyMsg); ok {
-			key := msg.String()
-			if key == "esc" {
-				t.mode = navigation // abandon changes
-			}
-			if key == "enter" {
-				if txt := t.itemEditor.Value(); txt != "" {
-					err := t.currentSelection().SetText(txt)
-					if err != nil {
-						fmt.Println("error: ", err)
-					}
-					t.mode = navigation
-				}
-			}
-		}
+	if t.mode == deleting {
+		cmd := t.updateDeleting(msg)
+		return t, cmd
+	}
 
-		var cmd tea.Cmd
-		t.itemEditor, cmd = t.itemEditor.Update(msg)
+	if t.mode == snoozing {
+		cmd := t.updateSnoozing(msg)
+		return t, cmd
+	}
+
+	if t.mode == edit {
+		cmd := t.updateEdit(msg)
 		return t, cmd
 	}
 
@@ -162,6 +163,30 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "?":
 			t.mode = help
+		case "+":
+			t.setScopeMode()
+		case "t":
+			return t, t.toggleClock()
+		case "f":
+			return t, t.setFocusMode()
+		case "S":
+			return t, t.setStatsMode()
+		case "P":
+			t.setPlanMode()
+		case "R":
+			t.setReviewMode()
+		case "i":
+			t.setInboxMode()
+		case "m":
+			t.setRefileMode()
+		case "A":
+			t.archiveSelection()
+		case "ctrl+a":
+			t.archiveListed()
+		case "D", "delete":
+			t.setDeleteMode()
+		case "u":
+			t.undoDeletion()
 		// editing current selection
 		case "x":
 			t.currentSelection().SetStatus(tuido.Checked)
@@ -179,51 +204,46 @@ func (t tui) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			t.currentSelection().SetStatus(tuido.Open)
 		case "!":
 			if t.currentSelection() != nil {
-				current := t.currentSelection()
 				t.currentSelection().Escalate()
-				t.populateRenderSelection()
-				for i, item := range t.renderSelection {
-					if current == item {
-						t.setSelection(i)
-					}
-				}
+				t.repopulateKeepingSelection()
 			}
 		case "1":
 			if t.currentSelection() != nil {
-				current := t.currentSelection()
 				t.currentSelection().Deescalate()
-				t.populateRenderSelection()
-				for i, item := range t.renderSelection {
-					if current == item {
-						t.setSelection(i)
-					}
-				}
+				t.repopulateKeepingSelection()
 			}
 		case "e":
 			t.setEditMode()
 		case "n":
-			t.tryCreateNewItem()
+			return t, t.tryCreateNewItem()
 		case "z":
-			t.currentSelection().Snooze()
+			t.snooze()
+		case "Z":
+			t.setSnoozeMode()
+		case "w":
+			t.err = t.currentSelection().Unsnooze()
 		case "enter":
 			t.setPeekMode()
+		case "o":
+			return t, t.openInEditor()
+		case "v":
+			t.toggleDetail()
+		case "d":
+			t.expanded = !t.expanded
 		case "q":
 			return t, tea.Quit
 		}
-
-	case tea.WindowSizeMsg:
-		t.h = msg.Height
-		t.w = msg.Width
 	}
-	return t, nil
+	return t, t.blameSelection()
 }
 
-func (t *tui) tryCreateNewItem() {
-	if len(t.renderSelection) >= 5 {
-		t.setNag("Too many items on your plate...", len(t.renderSelection)-4, navigation)
-	} else {
-		t.createNewItem()
+func (t *tui) tryCreateNewItem() tea.Cmd {
+	count, where := t.nagCount()
+	if level := t.nagPolicy.level(count); level > 0 {
+		return t.setNag(fmt.Sprintf("Too many items %s...", where), count, level)
 	}
+	t.createNewItem()
+	return nil
 }
 
 func (t *tui) createNewItem() {
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
 	lg "github.com/charmbracelet/lipgloss"
@@ -39,28 +40,39 @@ var ( // header styles
 )
 
 func (t tui) header() string {
-	var todoTab, doneTab string
+	renderedTabs := []string{}
 
-	if t.itemsFilter == todo {
-		todoTab = activeTabStyle.Render(string(todo))
-	} else {
-		todoTab = tabStyle.Render(string(todo))
+	for _, it := range itemTypes {
+		label := string(it)
+		if it == today {
+			label = t.todayLabel()
+		}
+		if t.itemsFilter == it {
+			renderedTabs = append(renderedTabs, activeTabStyle.Render(label))
+		} else {
+			renderedTabs = append(renderedTabs, tabStyle.Render(label))
+		}
 	}
 
-	if t.itemsFilter == done {
-		doneTab = activeTabStyle.Render(string(done))
-	} else {
-		doneTab = tabStyle.Render(string(done))
+	if t.scope != "" {
+		renderedTabs = append(renderedTabs,
+			tabGapStyle.Render(t.tagColors[string(t.scope)].Render(summarize(t.scope, t.items).String())))
 	}
 
-	tabs := lg.JoinHorizontal(lg.Bottom, todoTab, doneTab)
+	tabs := lg.JoinHorizontal(lg.Bottom, renderedTabs...)
 	searchBox := tabGapStyle.Render(t.filter.View())
 
 	helpPrompt := "? - help"
+	if inbox := len(t.inboxItems()); inbox > 0 {
+		helpPrompt = fmt.Sprintf("i - inbox (%d)  ", inbox) + helpPrompt
+	}
 	if len(t.notifs) > 0 {
 		helpPrompt += fmt.Sprintf(" (%d)", len(t.notifs))
 	}
 	helpPrompt = tabGapStyle.Copy().Faint(true).Render(helpPrompt)
+	if t.clock != nil {
+		helpPrompt = lg.JoinHorizontal(lg.Bottom, tabGapStyle.Render(t.clockView()+"  "), helpPrompt)
+	}
 
 	gap := tabGapStyle.Render(strings.Repeat(" ", max(0, t.w-lg.Width(
 		lg.JoinHorizontal(lg.Bottom, tabs, searchBox, helpPrompt))-5),
@@ -73,6 +85,9 @@ func (t tui) footer() string {
 	footStyle := tabStyle.Copy().BorderBottom(false).BorderLeft(false).BorderRight(false)
 
 	itemLoc := t.currentSelection().Location()
+	if t.mode == peek {
+		itemLoc = fmt.Sprintf("%s:%d", t.peek.item.File(), t.peek.cursor+1)
+	}
 	itemStr := footStyle.Render(itemLoc)
 
 	var right string
@@ -89,9 +104,16 @@ func (t tui) footer() string {
 			right = footStyle.Render(t.pagination())
 		} else if t.mode == edit {
 			right = footStyle.Copy().Faint(true).
-				Render("[enter] - Save Changes,  [esc] - Discard Changes")
+				Render("[enter] - Save Changes,  [ctrl+j] - Add Description Line,  [esc] - Discard Changes")
+		} else if t.mode == snoozing {
+			right = footStyle.Copy().Faint(true).
+				Render("[enter] - Snooze,  [esc] - Cancel")
+		} else if t.mode == deleting {
+			right = footStyle.Copy().Bold(true).
+				Render("Delete this item from disk? [y] - Delete,  [n] - Cancel")
 		} else if t.mode == peek {
-			right = footStyle.Copy().Faint(true).Render("[esc] - Return to list view")
+			right = footStyle.Copy().Faint(true).
+				Render("[/] - Search,  [[ ]] - Prev/Next Item,  [o] - Open in Editor,  [esc] - Return to list view")
 		}
 	}
 
@@ -130,28 +152,12 @@ func (t tui) View() string {
 	case nag:
 		return t.nag.View()
 	case pomo:
-		ret := t.renderedItemCollection(t.w)[t.selection] + "\n\n"
-		if t.pomoTimeRemaining > 0 {
-			if t.pomoTimeRemaining > 60 {
-				ret += fmt.Sprint(t.pomoTimeRemaining / 60)
-			} else {
-				ret += fmt.Sprint(t.pomoTimeRemaining)
-			}
-		} else {
-			ret += t.pomoEditor.View()
-		}
-
-		return lg.NewStyle().
-			Align(lg.Left).
-			Margin(2).
-			Width(t.w / 2).
-			Render(ret)
-
+		return t.pomoView()
 	case help:
 		controls := "\n[press any key to exit help]\n\n"
-		controls += "n: new item\ne: edit item\nz: snooze item\n!: escalate item\n1: relax item\np: begin a pomodoro\n\n"
+		controls += "n: new item\ne: edit item\nm: move item to another file\nA: archive done item\nctrl+a: archive all listed done items\nD: delete item\no: open item in $EDITOR\nv: toggle detail pane\nd: show/hide item descriptions\nu: undo delete\nz: snooze item\nZ: snooze item until...\nw: wake (unsnooze) item\n!: escalate item\n1: relax item\np: begin a pomodoro\n\n"
 		controls += "x: mark done\ns: mark obsolete (strikethrough)\na: mark ongoing (at)\n[space]: mark open\n\n"
-		controls += "[tab]: cycle between todo and done tabs\n/: filter todos by text\n?: enter help\n\n"
+		controls += "[tab]: cycle between todo, today, done, and snoozed tabs\nP: plan today\n/: filter todos by text\n+: pick a +project or @context\nt: start/stop the clock on item\nf: focus on item\nR: weekly review\nS: estimate vs spent stats\ni: process inbox\n?: enter help\n\n"
 		controls += "q: quit"
 
 		txt := lg.NewStyle().Width(28).Align(lg.Left).
@@ -162,7 +168,21 @@ func (t tui) View() string {
 
 		return lg.JoinVertical(lg.Left, notifications, lg.JoinHorizontal(lg.Top, "  ", controls, "    ", txt))
 	case peek:
-		return t.peek.View(t.h, t.w, t.footer)
+		return t.peekView()
+	case scoping:
+		return t.scopes.View()
+	case reviewing:
+		return t.reviewView()
+	case processing:
+		return t.inboxView()
+	case refiling:
+		return t.refileView()
+	case stats:
+		return t.statsView()
+	case focus:
+		return t.focusView()
+	case planning:
+		return t.planView()
 	default:
 		if len(t.renderSelection) == 0 { // init population
 			t.populateRenderSelection()
@@ -175,7 +195,7 @@ func (t tui) View() string {
 
 		availableHeight := t.h - (lg.Height(header) + lg.Height(footer))
 
-		body := t.renderVisibleListedItems(availableHeight, t.w)
+		body := t.listWithDetail(availableHeight, t.w)
 
 		// recalculate footer because pages data was set during body render
 		rows = append(rows, header, body, t.footer())
@@ -244,29 +264,122 @@ func (t tui) renderedItemCollection(width int) []string {
 			cursor := "> "
 			if t.mode == edit {
 				renderedItem = lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.itemEditor.View()))
+				if suggestions := t.completer.View(); suggestions != "" {
+					renderedItem = lg.JoinVertical(lg.Left, renderedItem, suggestions)
+				}
+				if description := t.descriptionEditorView(); description != "" {
+					renderedItem = lg.JoinVertical(lg.Left, renderedItem, description)
+				}
+				if preview := t.editPreview(); preview != "" {
+					renderedItem = lg.JoinVertical(lg.Left, renderedItem, preview)
+				}
 			} else {
 				renderedItem = lg.JoinHorizontal(lg.Top, cursor, selected.Render(t.renderTuido(*item, width)))
+				renderedItem = t.withDescription(renderedItem, *item, width)
+			}
+			if t.mode == snoozing {
+				renderedItem = lg.JoinVertical(lg.Left, renderedItem, "  "+t.snoozeEditor.View())
 			}
 
 		} else {
 			leadingSpace := "  "
 			renderedItem = lg.JoinHorizontal(lg.Top, leadingSpace, t.renderTuido(*item, width))
+			renderedItem = t.withDescription(renderedItem, *item, width)
 		}
 		renderedItems = append(renderedItems, renderedItem)
 	}
 	return renderedItems
 }
 
+// withDescription adds the item's description to its rendering, if
+// descriptions are expanded. They are read from disk only then, as the
+// list is rendered often.
+func (t tui) withDescription(rendered string, item tuido.Item, width int) string {
+	if !t.expanded {
+		return rendered
+	}
+	description := item.Description()
+	if len(description) == 0 {
+		return rendered
+	}
+	return lg.JoinVertical(lg.Left, rendered, descriptionView(description, width))
+}
+
+// editPreview describes the dates that natural-language and shorthand
+// phrases in the item editor will resolve to when saved, and the time
+// spent after manual time entries (+25m).
+func (t tui) editPreview() string {
+	txt := t.itemEditor.Value()
+	expanded := tuido.ExpandTimeEntries(tuido.ExpandShorthands(txt))
+	if expanded == txt {
+		return ""
+	}
+
+	spent := ""
+	for _, tag := range tuido.Tags(txt) {
+		if tag.Name() == "spent" {
+			spent = tag.Value()
+		}
+	}
+
+	resolved := []string{}
+	for _, tag := range tuido.Tags(expanded) {
+		if tag.Name() == "spent" && tag.Value() != spent {
+			if d, err := tuido.ParseEffort(tag.Value()); err == nil {
+				resolved = append(resolved, "spent: "+tuido.FormatEffort(d))
+			}
+		}
+		if tag.Name() != "due" && tag.Name() != "active" {
+			continue
+		}
+		if d, err := tuido.ParseDate(tag.Value()); err == nil {
+			layout := "Mon Jan 2 2006"
+			if strings.Contains(tag.Value(), "T") {
+				layout += " 15:04"
+			}
+			resolved = append(resolved, fmt.Sprintf("%s: %s", tag.Name(), d.Format(layout)))
+		}
+	}
+
+	if len(resolved) == 0 {
+		return ""
+	}
+	return lg.NewStyle().Faint(true).PaddingLeft(5).Render(strings.Join(resolved, "  "))
+}
+
+// colorScopeWords colors the +project and @context words of s, matching
+// whole words, so that eg +rel leaves +release alone.
+func (t tui) colorScopeWords(s string) string {
+	words := strings.Split(s, " ")
+	for i, w := range words {
+		runes := []rune(w)
+		if len(runes) < 2 || (runes[0] != '+' && runes[0] != '@') || !unicode.IsLetter(runes[1]) {
+			continue
+		}
+		if style, ok := t.tagColors[w]; ok {
+			words[i] = style.Render(w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
 // renderTuido applies tagColor to the items tags, splits long items
 // over multiple lines, and returns the text
 func (t tui) renderTuido(item tuido.Item, width int) string {
-	ret := item.String()
+	ret := t.colorScopeWords(item.String())
 	tags := item.Tags()
 
 	for _, tag := range tags {
 		ret = strings.ReplaceAll(ret, "#"+tag.String(), t.tagColors[tag.Name()].Render("#"+tag.String()))
 	}
 
+	if t.itemsFilter == snoozed {
+		if wakes := item.ActiveDate(); wakes != nil {
+			ret += fmt.Sprintf("  · wakes %s · snoozed %d time(s)",
+				wakes.Format("Mon Jan 2 15:04"), item.SnoozeCount())
+		}
+	}
+
 	// +2 here because of the leading 'cursor' space
 	if len(ret)+2 > width {
 		rowsRequired := (len(ret) - 4) / (width - 6) // -6 here instead of 4 because of the cursor spaces
@@ -290,3 +403,7 @@ func max(a, b int) int {
 	}
 	return b
 }
+
+func abs(a int) int {
+	return max(a, -a)
+}
@@ -0,0 +1,97 @@
+package tuido
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deletion records an item removed from disk, along with its continuation
+// lines, so that the removal can be undone.
+type Deletion struct {
+	Item *Item
+
+	// lines are the removed lines: the item, then its continuation lines
+	lines []string
+}
+
+// Delete removes the item and any continuation lines from its file.
+//
+// others are the in-memory items parsed from disk. Those following the
+// item in its file have their line numbers shifted to match.
+func (i *Item) Delete(others []*Item) (*Deletion, error) {
+	if i == nil {
+		return nil, fmt.Errorf("item is nil - cannot delete")
+	}
+
+	removed := []string{}
+	err := fileRewrite(i.file, func(lines []string) ([]string, error) {
+		if err := expectLine(lines, i.line, i.raw); err != nil {
+			return nil, err
+		}
+
+		end := i.line + 1
+		for end < len(lines) && isContinuation(i.scrap(), lines[end]) {
+			end++
+		}
+		removed = append(removed, lines[i.line:end]...)
+
+		return append(lines[:i.line], lines[end:]...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for range removed {
+		LineRemoved(siblings(i, others), i.file, i.line)
+	}
+
+	return &Deletion{Item: i, lines: removed}, nil
+}
+
+// Restore writes the deleted lines back to where they were removed from,
+// and shifts the line numbers of others to match.
+func (d *Deletion) Restore(others []*Item) error {
+	i := d.Item
+
+	err := fileRewrite(i.file, func(lines []string) ([]string, error) {
+		if i.line < 1 || i.line > len(lines) {
+			return nil, fmt.Errorf("cannot restore item: %s is now too short", i.file)
+		}
+		restored := append([]string{}, lines[:i.line]...)
+		restored = append(restored, d.lines...)
+		return append(restored, lines[i.line:]...), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for range d.lines {
+		LineInserted(siblings(i, others), i.file, i.line)
+	}
+	return nil
+}
+
+// isContinuation reports whether line continues the description of an
+// item with the given prefix: a non-blank, non-item line indented four
+// spaces further than the item's status box.
+func isContinuation(prefix, line string) bool {
+	if strings.TrimSpace(line) == "" || IsTuido(line) {
+		return false
+	}
+
+	indent := strings.Repeat(" ", 4)
+	aligned := strings.Repeat(" ", len(prefix))
+	return strings.HasPrefix(line, prefix+indent) ||
+		strings.HasPrefix(line, aligned+indent)
+}
+
+// siblings returns others, less i.
+func siblings(i *Item, others []*Item) []*Item {
+	ret := []*Item{}
+	for _, other := range others {
+		if other != i {
+			ret = append(ret, other)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,73 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.xit")
+	contents := "[ ] first\n[ ] second\n    with more detail\n    over two lines\n[ ] third\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*Item{}
+	for _, loc := range []struct {
+		line int
+		raw  string
+	}{{1, "[ ] first"}, {2, "[ ] second"}, {5, "[ ] third"}} {
+		item := New(file, loc.line, loc.raw)
+		items = append(items, &item)
+	}
+
+	deletion, err := items[1].Delete(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(file)
+	if string(got) != "[ ] first\n[ ] third\n" {
+		t.Errorf("unexpected contents after delete %q", got)
+	}
+	if items[2].Line() != 2 {
+		t.Errorf("expected following item at line 2, but found %d", items[2].Line())
+	}
+
+	if err := deletion.Restore(items); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ = os.ReadFile(file)
+	if string(got) != contents {
+		t.Errorf("unexpected contents after restore %q", got)
+	}
+	if items[2].Line() != 5 {
+		t.Errorf("expected following item back at line 5, but found %d", items[2].Line())
+	}
+	if err := items[1].SetStatus(Checked); err != nil {
+		t.Errorf("unexpected error updating restored item: %s", err)
+	}
+}
+
+func TestIsContinuation(t *testing.T) {
+	cases := []struct {
+		prefix, line string
+		expected     bool
+	}{
+		{"", "    more detail", true},
+		{"", "  not enough indentation", false},
+		{"", "    [ ] a nested item", false},
+		{"", "    ", false},
+		{"- ", "      more detail", true},
+		{"// ", "//     more detail", true},
+		{"// ", "// another comment", false},
+	}
+
+	for _, c := range cases {
+		if got := isContinuation(c.prefix, c.line); got != c.expected {
+			t.Errorf("isContinuation(%q, %q): expected %t, got %t", c.prefix, c.line, c.expected, got)
+		}
+	}
+}
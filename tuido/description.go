@@ -0,0 +1,93 @@
+package tuido
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Description returns the item's description: the text of the
+// continuation lines that follow it in its file.
+func (i Item) Description() []string {
+	lines, err := Source(i.file)
+	if err != nil || i.line < 1 || i.line > len(lines) || lines[i.line-1] != i.raw {
+		return nil
+	}
+
+	description := []string{}
+	for _, l := range lines[i.line:] {
+		if !isContinuation(i.scrap(), l) {
+			break
+		}
+		description = append(description, strings.TrimSpace(strings.TrimPrefix(l, commentMarker(i.scrap()))))
+	}
+	return description
+}
+
+// SetDescription replaces the item's continuation lines on disk with
+// lines, indented to follow the item. Blank lines are dropped, as they
+// would end the description.
+//
+// others are the in-memory items parsed from disk. Those following the
+// item in its file have their line numbers shifted to match.
+func (i *Item) SetDescription(lines []string, others []*Item) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot update description")
+	}
+
+	description := []string{}
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			description = append(description, strings.TrimSpace(l))
+		}
+	}
+
+	indent := continuationPrefix(i.scrap())
+	removed := 0
+	err := fileRewrite(i.file, func(fileLines []string) ([]string, error) {
+		if err := expectLine(fileLines, i.line, i.raw); err != nil {
+			return nil, err
+		}
+
+		end := i.line + 1
+		for end < len(fileLines) && isContinuation(i.scrap(), fileLines[end]) {
+			end++
+		}
+		removed = end - i.line - 1
+
+		updated := append([]string{}, fileLines[:i.line+1]...)
+		for _, l := range description {
+			updated = append(updated, indent+l)
+		}
+		return append(updated, fileLines[end:]...), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for n := 0; n < removed; n++ {
+		LineRemoved(siblings(i, others), i.file, i.line)
+	}
+	for range description {
+		LineInserted(siblings(i, others), i.file, i.line+1)
+	}
+
+	return nil
+}
+
+// commentMarker returns the comment marker in an item's prefix, if any.
+func commentMarker(prefix string) string {
+	if strings.TrimLeft(prefix, " \t") == "// " {
+		return prefix
+	}
+	return ""
+}
+
+// continuationPrefix is the leading text of continuation lines for an
+// item with the given prefix: comment markers are kept, and bullets
+// replaced with spaces, followed by the four space [x]it! indent.
+func continuationPrefix(prefix string) string {
+	if marker := commentMarker(prefix); marker != "" {
+		return marker + strings.Repeat(" ", 4)
+	}
+	return strings.Repeat(" ", len(prefix)+4)
+}
@@ -0,0 +1,83 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescription(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.md")
+	contents := strings.Join([]string{
+		"[ ] first",
+		"    with a description",
+		"    over two lines",
+		"[ ] second",
+		"not a description",
+		"- [ ] bulleted",
+		"      described",
+		"// [ ] commented",
+		"//     described in a comment",
+	}, "\n")
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		line     int
+		expected []string
+	}{
+		{1, []string{"with a description", "over two lines"}},
+		{4, []string{}},
+		{6, []string{"described"}},
+		{8, []string{"described in a comment"}},
+	}
+
+	lines := strings.Split(contents, "\n")
+	for _, c := range cases {
+		item := New(file, c.line, lines[c.line-1])
+		if got := item.Description(); !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("line %d: expected description %q, got %q", c.line, c.expected, got)
+		}
+	}
+}
+
+func TestSetDescription(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.md")
+	contents := "- [ ] first\n      old description\n- [ ] second\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := New(file, 1, "- [ ] first"), New(file, 3, "- [ ] second")
+	items := []*Item{&first, &second}
+
+	if err := items[0].SetDescription([]string{"new", "", "  description  "}, items); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := os.ReadFile(file)
+	if string(got) != "- [ ] first\n      new\n      description\n- [ ] second\n" {
+		t.Errorf("unexpected contents %q", got)
+	}
+	if items[1].Line() != 4 {
+		t.Errorf("expected following item at line 4, but found %d", items[1].Line())
+	}
+	if err := items[1].SetStatus(Checked); err != nil {
+		t.Errorf("unexpected error updating shifted item: %s", err)
+	}
+
+	if d := items[0].Description(); !reflect.DeepEqual(d, []string{"new", "description"}) {
+		t.Errorf("unexpected description %q", d)
+	}
+
+	if err := items[0].SetDescription(nil, items); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(file)
+	if !strings.HasPrefix(string(got), "- [ ] first\n- [x] second") {
+		t.Errorf("unexpected contents after clearing description %q", got)
+	}
+}
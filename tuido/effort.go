@@ -0,0 +1,186 @@
+package tuido
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WorkDay is the length of a 1d estimate: a day of work, rather than
+	// of the clock.
+	WorkDay = 8 * time.Hour
+	// WorkWeek is the length of a 1w estimate.
+	WorkWeek = 5 * WorkDay
+)
+
+// ParseEffort reads an amount of work, as written in #estimate and
+// #spent tags. Amounts are sequences of numbers and units, eg 25m, 1.5h,
+// or 1h15m, where the units are:
+//   - w: work weeks (see WorkWeek)
+//   - d: work days (see WorkDay)
+//   - h: hours
+//   - m: minutes
+//   - s: seconds
+//
+// A bare number is a number of minutes, as older versions of tuido
+// wrote #spent tags (eg, 37.50).
+func ParseEffort(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty effort")
+	}
+
+	if minutes, err := strconv.ParseFloat(s, 64); err == nil {
+		if minutes < 0 {
+			return 0, fmt.Errorf("negative effort: %s", s)
+		}
+		return time.Duration(minutes * float64(time.Minute)), nil
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		n := strings.IndexFunc(rest, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.'
+		})
+		if n <= 0 {
+			return 0, fmt.Errorf("invalid effort: %s", s)
+		}
+		num, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid effort: %s", s)
+		}
+
+		var unit time.Duration
+		switch rest[n] {
+		case 'w':
+			unit = WorkWeek
+		case 'd':
+			unit = WorkDay
+		case 'h':
+			unit = time.Hour
+		case 'm':
+			unit = time.Minute
+		case 's':
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("invalid effort unit in %s: %c", s, rest[n])
+		}
+		total += time.Duration(num * float64(unit))
+		rest = rest[n+1:]
+	}
+	return total, nil
+}
+
+// FormatEffort writes d in hours and minutes, eg 1h15m, rounded to the
+// minute. It is read back by ParseEffort.
+func FormatEffort(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%dm", h, m)
+}
+
+// Estimate returns the work the item is expected to take, from its
+// #estimate tag, or nil if it has no readable estimate.
+func (i Item) Estimate() *time.Duration {
+	d, err := ParseEffort(i.tagValue("estimate"))
+	if err != nil {
+		return nil
+	}
+	return &d
+}
+
+// Spent returns the time recorded against the item in its #spent tag.
+func (i Item) Spent() time.Duration {
+	d, err := ParseEffort(i.tagValue("spent"))
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// formatSpent writes d as the value of a #spent tag, like FormatEffort,
+// but to the second, so that short stretches of time add up.
+func formatSpent(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d == 0 {
+		return "0m"
+	}
+
+	h, m, s := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	ret := ""
+	if h > 0 {
+		ret += fmt.Sprintf("%dh", h)
+	}
+	if m > 0 {
+		ret += fmt.Sprintf("%dm", m)
+	}
+	if s > 0 {
+		ret += fmt.Sprintf("%ds", s)
+	}
+	return ret
+}
+
+// LegacySpent reports whether the item's #spent tag is in the minutes
+// format written by older versions of tuido, eg #spent=37.50.
+func (i Item) LegacySpent() bool {
+	_, err := strconv.ParseFloat(i.tagValue("spent"), 64)
+	return err == nil
+}
+
+// MigrateSpent rewrites a #spent tag in the legacy minutes format as a
+// duration, eg #spent=37m30s. It reports whether the item needed
+// migrating.
+func (i *Item) MigrateSpent() (bool, error) {
+	if !i.LegacySpent() {
+		return false, nil
+	}
+	return true, i.setTag(Tag{"spent", formatSpent(i.Spent())})
+}
+
+// ExpandTimeEntries adds manual time entries in txt, like +25m or
+// +1h30m, to its #spent tag, removing the entries. Entries need a unit,
+// so that eg "+1" is left alone.
+//
+// Unlike shorthands, entries are not expanded by SetText: they are only
+// meant as such when typed into the item editor.
+func ExpandTimeEntries(txt string) string {
+	var logged time.Duration
+	kept := []string{}
+
+	for _, w := range strings.Split(txt, " ") {
+		if len(w) > 2 && w[0] == '+' && w[1] >= '0' && w[1] <= '9' && strings.ContainsAny(w[len(w)-1:], "wdhms") {
+			if d, err := ParseEffort(w[1:]); err == nil {
+				logged += d
+				continue
+			}
+		}
+		kept = append(kept, w)
+	}
+	if logged == 0 {
+		return txt
+	}
+
+	txt = strings.Join(kept, " ")
+	spent, _ := ParseEffort(tagValue(txt, "spent"))
+	return setTagText(txt, Tag{"spent", formatSpent(spent + logged)})
+}
+
+// tagValue returns the value of the first tag in txt with the given
+// name, or "" if there is no such tag.
+func tagValue(txt, name string) string {
+	for _, t := range Tags(txt) {
+		if t.name == name {
+			return t.value
+		}
+	}
+	return ""
+}
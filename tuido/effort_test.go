@@ -0,0 +1,217 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseEffort(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"25m", 25 * time.Minute},
+		{"2h", 2 * time.Hour},
+		{"1h15m", 75 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"1d", WorkDay},
+		{"1w2d", 7 * WorkDay},
+		{"90s", 90 * time.Second},
+		{"37.50", 37*time.Minute + 30*time.Second},
+		{"0", 0},
+	}
+
+	for _, test := range tests {
+		got, err := ParseEffort(test.input)
+		if err != nil {
+			t.Errorf("ParseEffort(%q): unexpected error %s", test.input, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("ParseEffort(%q): expected %s, but found %s", test.input, test.expected, got)
+		}
+	}
+
+	for _, invalid := range []string{"", "h", "2x", "1h30", "-5", "soon"} {
+		if _, err := ParseEffort(invalid); err == nil {
+			t.Errorf("ParseEffort(%q): expected an error", invalid)
+		}
+	}
+}
+
+func TestFormatEffort(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                               "0m",
+		45 * time.Minute:                "45m",
+		2 * time.Hour:                   "2h",
+		75*time.Minute + 20*time.Second: "1h15m",
+		WorkDay + time.Minute:           "8h1m",
+	}
+	for d, expected := range tests {
+		if got := FormatEffort(d); got != expected {
+			t.Errorf("FormatEffort(%s): expected %s, but found %s", d, expected, got)
+		}
+		if parsed, _ := ParseEffort(FormatEffort(d)); parsed != d.Round(time.Minute) {
+			t.Errorf("FormatEffort(%s) does not read back: %s", d, parsed)
+		}
+	}
+}
+
+func TestEffortReport(t *testing.T) {
+	raws := []string{
+		"[x] write docs #estimate=1h #spent=90 +release #docs #completed=2026-10-14",
+		"[x] fix bug #estimate=2h #spent=1h +release #completed=2026-10-20",
+		"[ ] refactor #estimate=30m #spent=45m #docs",
+		"[ ] untracked #docs",
+	}
+	items := []*Item{}
+	for n, raw := range raws {
+		item := New("list.xit", n+1, raw)
+		items = append(items, &item)
+	}
+
+	total := EffortTotal(items)
+	if total.Items != 3 || total.Estimate != 3*time.Hour+30*time.Minute || total.Spent != 3*time.Hour+15*time.Minute {
+		t.Errorf("unexpected totals %+v", total)
+	}
+	if total.Overruns != 2 {
+		t.Errorf("expected 2 overruns, but found %d", total.Overruns)
+	}
+	if ratio, ok := total.Accuracy(); !ok || ratio != 2.5/3 {
+		t.Errorf("expected accuracy of %f, but found %f", 2.5/3, ratio)
+	}
+
+	byProject := EffortReport(items, ByProject)
+	if len(byProject) != 2 || byProject[0].Group != "+release" || byProject[1].Group != ungrouped {
+		t.Fatalf("unexpected project groups %+v", byProject)
+	}
+	if byProject[0].Items != 2 || byProject[1].Items != 1 {
+		t.Errorf("unexpected project counts %+v", byProject)
+	}
+
+	byTag := EffortReport(items, ByTag)
+	if len(byTag) != 2 || byTag[0].Group != "#docs" || byTag[0].Items != 2 {
+		t.Errorf("unexpected tag groups %+v", byTag)
+	}
+
+	byWeek := EffortReport(items, ByWeek)
+	weeks := []string{}
+	for _, s := range byWeek {
+		weeks = append(weeks, s.Group)
+	}
+	if len(weeks) != 3 || weeks[0] != "2026-W42" || weeks[1] != "2026-W43" || weeks[2] != ungrouped {
+		t.Errorf("unexpected weeks %v", weeks)
+	}
+}
+
+func TestExpandTimeEntries(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"write docs +25m", "write docs #spent=25m"},
+		{"write docs +1h +30m #spent=15.00 +release", "write docs #spent=1h45m +release"},
+		{"write docs #spent=1h +5m", "write docs #spent=1h5m"},
+		{"write docs +release", "write docs +release"},
+		{"count +1 apples +2x", "count +1 apples +2x"},
+	}
+
+	for _, test := range tests {
+		if got := ExpandTimeEntries(test.input); got != test.expected {
+			t.Errorf("ExpandTimeEntries(%q): expected %q, but found %q", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestSetTagKeepsTimeEntries(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.xit")
+	if err := os.WriteFile(file, []byte("[ ] raise timeout +30s\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	item := New(file, 1, "[ ] raise timeout +30s")
+	if err := item.SetCompleted(time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)); err != nil {
+		t.Fatal(err)
+	}
+	if item.Text() != "raise timeout +30s #completed=2026-10-19" {
+		t.Errorf("expected the +30s to be kept, but found %q", item.Text())
+	}
+}
+
+func TestIncrementTimeSpent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.xit")
+	if err := os.WriteFile(file, []byte("[ ] tracked #spent=1h\n[ ] garbled #spent=lots\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	tracked := New(file, 1, "[ ] tracked #spent=1h")
+	if err := tracked.IncrementTimeSpent(90); err != nil {
+		t.Fatal(err)
+	}
+	if tracked.Text() != "tracked #spent=1h1m30s" {
+		t.Errorf("expected 1h1m30s spent, but found %q", tracked.Text())
+	}
+
+	garbled := New(file, 2, "[ ] garbled #spent=lots")
+	if err := garbled.IncrementTimeSpent(90); err == nil {
+		t.Error("expected an error adding to an unparseable #spent")
+	}
+	if garbled.Text() != "garbled #spent=lots" {
+		t.Errorf("expected the unparseable #spent to be left alone, but found %q", garbled.Text())
+	}
+}
+
+func TestFormatSpent(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                                   "0m",
+		40 * time.Second:                    "40s",
+		37*time.Minute + 30*time.Second:     "37m30s",
+		time.Hour + 5*time.Second:           "1h5s",
+		75 * time.Minute:                    "1h15m",
+		2*time.Hour + 1500*time.Millisecond: "2h2s",
+		10*time.Minute + 30*time.Second:     "10m30s",
+		20*time.Minute + 30*time.Second:     "20m30s",
+		70*time.Minute + 5*time.Second:      "1h10m5s",
+		2 * time.Hour:                       "2h",
+	}
+	for d, expected := range tests {
+		got := formatSpent(d)
+		if got != expected {
+			t.Errorf("formatSpent(%s): expected %s, but found %s", d, expected, got)
+		}
+		if parsed, _ := ParseEffort(got); parsed != d.Round(time.Second) {
+			t.Errorf("formatSpent(%s) does not read back: %s", d, parsed)
+		}
+	}
+}
+
+func TestMigrateSpent(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.xit")
+	contents := "[ ] legacy #spent=37.50 #estimate=1h\n[ ] current #spent=1h15m\n[ ] round #spent=10.50\n[ ] long #spent=70.0833\n[ ] longer #spent=20.50\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+	legacy, current := New(file, 1, "[ ] legacy #spent=37.50 #estimate=1h"), New(file, 2, "[ ] current #spent=1h15m")
+
+	if migrated, err := legacy.MigrateSpent(); !migrated || err != nil {
+		t.Errorf("expected legacy item to migrate, but found %v, %v", migrated, err)
+	}
+	if migrated, err := current.MigrateSpent(); migrated || err != nil {
+		t.Errorf("expected current item to be left alone, but found %v, %v", migrated, err)
+	}
+
+	for i, raw := range []string{"[ ] round #spent=10.50", "[ ] long #spent=70.0833", "[ ] longer #spent=20.50"} {
+		item := New(file, i+3, raw)
+		if migrated, err := item.MigrateSpent(); !migrated || err != nil {
+			t.Errorf("expected %q to migrate, but found %v, %v", raw, migrated, err)
+		}
+	}
+
+	got, _ := os.ReadFile(file)
+	if string(got) != "[ ] legacy #spent=37m30s #estimate=1h\n[ ] current #spent=1h15m\n"+
+		"[ ] round #spent=10m30s\n[ ] long #spent=1h10m5s\n[ ] longer #spent=20m30s\n" {
+		t.Errorf("unexpected contents after migration %q", got)
+	}
+}
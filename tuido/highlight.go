@@ -0,0 +1,162 @@
+package tuido
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/nilock/tuido/utils"
+)
+
+// highlightChunk is the number of lines highlighted together. Files are
+// highlighted a chunk at a time, as needed for display, so that large
+// files are not highlighted in full to show a few lines.
+const highlightChunk = 200
+
+// source is a cached copy of a file's contents.
+type source struct {
+	modTime time.Time
+	size    int64
+
+	lines []string
+	lexer chroma.Lexer
+	// chunks holds highlighted lines, by chunk index
+	chunks map[int][]string
+}
+
+var (
+	sourceCache   = map[string]*source{}
+	sourceCacheMu sync.Mutex
+)
+
+// loadSource returns the cached contents of file, reading it again
+// if it has changed on disk since it was cached.
+//
+// The caller must hold sourceCacheMu.
+func loadSource(file string) (*source, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached, ok := sourceCache[file]; ok &&
+		cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached, nil
+	}
+
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1] // trailing newline
+	}
+
+	s := &source{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		lines:   lines,
+		lexer:   lexerFor(file, contents),
+		chunks:  map[int][]string{},
+	}
+	sourceCache[file] = s
+	return s, nil
+}
+
+// lexerFor picks a lexer by file name, or else by content, falling back
+// to plain text.
+func lexerFor(file string, contents []byte) chroma.Lexer {
+	lexer := lexers.Match(file)
+	if lexer == nil {
+		lexer = lexers.Analyse(string(contents))
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// highlighted returns the nth chunk of lines, colorized for the terminal.
+func (s *source) highlighted(n int) []string {
+	if chunk, ok := s.chunks[n]; ok {
+		return chunk
+	}
+
+	first := n * highlightChunk
+	last := min(first+highlightChunk, len(s.lines))
+	plain := s.lines[first:last]
+
+	chunk := plain
+	iterator, err := s.lexer.Tokenise(nil, strings.Join(plain, "\n"))
+	if err == nil {
+		buf := new(bytes.Buffer)
+		formatter := formatters.Get(utils.GetTerminalColorSupport())
+		if formatter.Format(buf, styles.Get("monokai"), iterator) == nil {
+			chunk = strings.Split(buf.String(), "\n")
+		}
+	}
+	// highlighting should preserve lines, but never trust it to
+	if len(chunk) < len(plain) {
+		chunk = plain
+	}
+
+	s.chunks[n] = chunk
+	return chunk
+}
+
+// Source returns the lines of file.
+func Source(file string) ([]string, error) {
+	sourceCacheMu.Lock()
+	defer sourceCacheMu.Unlock()
+
+	s, err := loadSource(file)
+	if err != nil {
+		return nil, err
+	}
+	return s.lines, nil
+}
+
+// Highlighted returns lines first to last (exclusive, counting from 0)
+// of file, syntax highlighted for display in the terminal. The range is
+// clamped to the lines of the file.
+func Highlighted(file string, first, last int) ([]string, error) {
+	sourceCacheMu.Lock()
+	defer sourceCacheMu.Unlock()
+
+	s, err := loadSource(file)
+	if err != nil {
+		return nil, err
+	}
+
+	first = max(0, first)
+	last = min(last, len(s.lines))
+
+	lines := []string{}
+	for line := first; line < last; line++ {
+		chunk := s.highlighted(line / highlightChunk)
+		lines = append(lines, chunk[line%highlightChunk])
+	}
+	return lines, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHighlighted(t *testing.T) {
+	// an extension that no lexer claims
+	file := filepath.Join(t.TempDir(), "notes.unknownext")
+	lines := []string{}
+	for i := 0; i < highlightChunk+10; i++ {
+		lines = append(lines, "line")
+	}
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := Source(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != len(lines) {
+		t.Errorf("expected %d lines, got %d", len(lines), len(plain))
+	}
+
+	// a window across chunks, clamped to the end of the file
+	window, err := Highlighted(file, highlightChunk-5, len(lines)+20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(window) != 15 {
+		t.Errorf("expected 15 highlighted lines, got %d", len(window))
+	}
+	for _, l := range window {
+		if !strings.Contains(l, "line") {
+			t.Errorf("unexpected highlighted line %q", l)
+		}
+	}
+
+	// changes on disk invalidate the cache
+	later := time.Now().Add(time.Minute)
+	if err := os.WriteFile(file, []byte("changed\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(file, later, later)
+
+	plain, err = Source(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != 1 || plain[0] != "changed" {
+		t.Errorf("expected re-read contents, got %q", plain)
+	}
+}
@@ -0,0 +1,267 @@
+package tuido
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File returns the path of the item's source file.
+func (i Item) File() string {
+	return i.file
+}
+
+// InFile reports whether the item's source file is file, however either
+// path is written.
+func (i Item) InFile(file string) bool {
+	return sameFile(i.file, file)
+}
+
+// Line returns the item's line number in its source file.
+func (i Item) Line() int {
+	return i.line
+}
+
+// Group is an [x]it! group: a run of consecutive non-blank lines containing
+// items, separated from other groups by blank lines. A group may be led
+// by a title line.
+type Group struct {
+	Title string
+
+	// first and last are the line numbers of the group's first and last lines
+	first, last int
+	// prefix is the indentation, bullet, or comment marker leading the group's items
+	prefix string
+}
+
+// Line returns the line number of the first line of the group.
+func (g Group) Line() int {
+	return g.first
+}
+
+func (g Group) String() string {
+	if g.Title != "" {
+		return g.Title
+	}
+	return fmt.Sprintf("untitled group at line %d", g.first)
+}
+
+// Groups returns the item groups in file, in order of appearance.
+func Groups(file string) ([]Group, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	groups := []Group{}
+	var current *Group
+	hasItems := false
+
+	closeGroup := func() {
+		if current != nil && hasItems {
+			groups = append(groups, *current)
+		}
+		current = nil
+		hasItems = false
+	}
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		txt := scanner.Text()
+
+		if strings.TrimSpace(txt) == "" {
+			closeGroup()
+			continue
+		}
+
+		if current == nil {
+			current = &Group{first: line}
+			if !IsTuido(txt) {
+				current.Title = strings.TrimSpace(txt)
+			}
+		}
+		current.last = line
+
+		if IsTuido(txt) && !hasItems {
+			hasItems = true
+			current.prefix = prefixOf(Item{raw: txt})
+		}
+	}
+	closeGroup()
+
+	return groups, scanner.Err()
+}
+
+// prefixOf returns the item's leading indentation plus bullet or comment
+// marker, or "" if the item is preceded by other content (eg, code).
+func prefixOf(i Item) string {
+	scrap := i.scrap()
+	switch strings.TrimLeft(scrap, " \t") {
+	case "", "- ", "// ":
+		return scrap
+	}
+	return ""
+}
+
+// defaultPrefix is the leading text for items written into a file
+// of the given type, with no neighbouring items to take after.
+func defaultPrefix(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xit", ".txt", "":
+		return ""
+	case ".md", ".markdown":
+		return "- "
+	}
+	// assume source code with c-style comments, as parsed by trim
+	return "// "
+}
+
+// MoveTo relocates the item from its source file to the end of target,
+// creating target if it does not exist. See RefileTo.
+func (i *Item) MoveTo(target string, others []*Item) error {
+	if i != nil && sameFile(i.file, target) {
+		return fmt.Errorf("item is already in %s", target)
+	}
+	return i.RefileTo(target, nil, others)
+}
+
+// RefileTo relocates the item, along with its description, to the end of
+// group in target, or to the end of target if group is nil. The item is
+// written with the prefix of the group's other items, or with the bullet
+// or comment marker suited to target's file type, and its description is
+// indented to follow.
+//
+// others are the in-memory items parsed from disk. Those following the
+// item in its source file, or the insertion point in target, have their
+// line numbers shifted to match.
+func (i *Item) RefileTo(target string, group *Group, others []*Item) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot move")
+	}
+
+	prefix := defaultPrefix(target)
+	insertAt := -1 // end of file
+	if group != nil {
+		prefix = group.prefix
+		insertAt = group.last + 1
+	}
+	block, err := i.block()
+	if err != nil {
+		return err
+	}
+	moved := []string{prefix + i.Satus().String() + " " + i.Text()}
+	for _, l := range block[1:] {
+		moved = append(moved, continuationPrefix(prefix)+strings.TrimSpace(strings.TrimPrefix(l, commentMarker(i.scrap()))))
+	}
+
+	// write the new copy before removing the old, so that a failure
+	// leaves a duplicate rather than losing the item.
+	insertAt, err = fileInsertLines(target, insertAt, moved)
+	if err != nil {
+		return err
+	}
+
+	same := sameFile(i.file, target)
+	source := i.line
+	if same && source >= insertAt {
+		source += len(moved)
+	}
+
+	err = fileRemoveLines(i.file, source, block)
+	if err != nil {
+		fileRemoveLines(target, insertAt, moved)
+		return err
+	}
+
+	for range moved {
+		LineInserted(siblings(i, others), target, insertAt)
+	}
+	for range block {
+		LineRemoved(siblings(i, others), i.file, source)
+	}
+
+	if same && source < insertAt {
+		insertAt -= len(block)
+	}
+
+	i.file = target
+	i.line = insertAt
+	i.raw = moved[0]
+	return nil
+}
+
+// block returns the item's line in its file, followed by its
+// continuation lines.
+func (i Item) block() ([]string, error) {
+	contents, err := os.ReadFile(i.file)
+	if err != nil {
+		return nil, err
+	}
+	lines := append([]string{""}, strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")...)
+	if err := expectLine(lines, i.line, i.raw); err != nil {
+		return nil, err
+	}
+
+	end := i.line + 1
+	for end < len(lines) && isContinuation(i.scrap(), lines[end]) {
+		end++
+	}
+	return lines[i.line:end], nil
+}
+
+// LineRemoved shifts up the line numbers of items that followed
+// a line removed from file.
+func LineRemoved(items []*Item, file string, line int) {
+	for _, item := range items {
+		if sameFile(item.file, file) && item.line > line {
+			item.line--
+		}
+	}
+}
+
+// LineInserted shifts down the line numbers of items at or after
+// a line inserted into file.
+func LineInserted(items []*Item, file string, line int) {
+	for _, item := range items {
+		if sameFile(item.file, file) && item.line >= line {
+			item.line++
+		}
+	}
+}
+
+func sameFile(a, b string) bool {
+	a, errA := filepath.Abs(a)
+	b, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && a == b
+}
+
+// fileInsertLines writes texts as new lines of file, such that the first
+// becomes line number `at`. An `at` of -1 (or past the end) appends to the
+// file. The file and its parent directories are created if necessary.
+//
+// It returns the line number of the first inserted line.
+func fileInsertLines(file string, at int, texts []string) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0777); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(file, os.O_RDONLY|os.O_CREATE, 0666)
+	if err != nil {
+		return 0, err
+	}
+	f.Close()
+
+	err = fileRewrite(file, func(lines []string) ([]string, error) {
+		if at < 1 || at > len(lines) {
+			at = len(lines)
+		}
+		lines = append(lines[:at], append(append([]string{}, texts...), lines[at:]...)...)
+		return lines, nil
+	})
+
+	return at, err
+}
@@ -0,0 +1,190 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveTo(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "inbox.xit")
+	target := filepath.Join(dir, "projects", "release.xit")
+
+	if err := os.WriteFile(source, []byte("[ ] first\n- [ ] second\n[ ] third\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*Item{}
+	for i, raw := range []string{"[ ] first", "- [ ] second", "[ ] third"} {
+		item := New(source, i+1, raw)
+		items = append(items, &item)
+	}
+
+	if err := items[1].MoveTo(target, items); err != nil {
+		t.Fatal(err)
+	}
+
+	if items[1].File() != target || items[1].Line() != 1 || items[1].raw != "[ ] second" {
+		t.Errorf("expected moved item at %s:1, but found %s (%q)", target, items[1].Location(), items[1].raw)
+	}
+	if items[0].Line() != 1 || items[2].Line() != 2 {
+		t.Errorf("expected remaining items at lines 1 and 2, but found %d and %d", items[0].Line(), items[2].Line())
+	}
+
+	src, _ := os.ReadFile(source)
+	if string(src) != "[ ] first\n[ ] third\n" {
+		t.Errorf("unexpected source contents %q", src)
+	}
+	dst, _ := os.ReadFile(target)
+	if string(dst) != "[ ] second\n" {
+		t.Errorf("unexpected target contents %q", dst)
+	}
+
+	// the remaining items are still writable at their new locations
+	if err := items[2].SetStatus(Ongoing); err != nil {
+		t.Errorf("unexpected error updating shifted item: %s", err)
+	}
+}
+
+func TestRefileTo(t *testing.T) {
+	dir := t.TempDir()
+	xit := filepath.Join(dir, "work.xit")
+	md := filepath.Join(dir, "notes.md")
+
+	xitContents := "Today\n[ ] one\n[ ] two\n\nLater\n[ ] three\n"
+	if err := os.WriteFile(xit, []byte(xitContents), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(md, []byte("# notes\n\n  - [ ] bulleted\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := Groups(xit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || groups[0].Title != "Today" || groups[1].Title != "Later" {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+
+	items := []*Item{}
+	for _, raw := range []struct {
+		line int
+		raw  string
+	}{{2, "[ ] one"}, {3, "[ ] two"}, {6, "[ ] three"}} {
+		item := New(xit, raw.line, raw.raw)
+		items = append(items, &item)
+	}
+
+	// within a file, from the second group to the end of the first
+	if err := items[2].RefileTo(xit, &groups[0], items); err != nil {
+		t.Fatal(err)
+	}
+	contents, _ := os.ReadFile(xit)
+	if string(contents) != "Today\n[ ] one\n[ ] two\n[ ] three\n\nLater\n" {
+		t.Errorf("unexpected contents after refile %q", contents)
+	}
+	if items[2].Line() != 4 {
+		t.Errorf("expected refiled item at line 4, but found %d", items[2].Line())
+	}
+
+	// into a markdown group, taking on its indentation and bullet
+	mdGroups, _ := Groups(md)
+	if err := items[0].RefileTo(md, &mdGroups[0], items); err != nil {
+		t.Fatal(err)
+	}
+	contents, _ = os.ReadFile(md)
+	if string(contents) != "# notes\n\n  - [ ] bulleted\n  - [ ] one\n" {
+		t.Errorf("unexpected markdown contents after refile %q", contents)
+	}
+	if items[1].Line() != 2 || items[2].Line() != 3 {
+		t.Errorf("expected remaining items at lines 2 and 3, but found %d and %d", items[1].Line(), items[2].Line())
+	}
+
+	// and to the end of a new source file, as a comment
+	goFile := filepath.Join(dir, "main.go")
+	if err := items[1].MoveTo(goFile, items); err != nil {
+		t.Fatal(err)
+	}
+	contents, _ = os.ReadFile(goFile)
+	if string(contents) != "// [ ] two\n" {
+		t.Errorf("unexpected go contents after move %q", contents)
+	}
+}
+
+func TestRefileWithDescription(t *testing.T) {
+	dir := t.TempDir()
+	xit := filepath.Join(dir, "work.xit")
+	md := filepath.Join(dir, "notes.md")
+
+	xitContents := "[ ] one\n    first note\n    second note\n[ ] two\n\n[ ] three\n"
+	if err := os.WriteFile(xit, []byte(xitContents), 0666); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(md, []byte("# notes\n\n  - [ ] bulleted\n\nafter\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*Item{}
+	for _, raw := range []struct {
+		line int
+		raw  string
+	}{{1, "[ ] one"}, {4, "[ ] two"}, {6, "[ ] three"}} {
+		item := New(xit, raw.line, raw.raw)
+		items = append(items, &item)
+	}
+
+	// the description moves with its item, indented to follow it
+	mdGroups, _ := Groups(md)
+	if err := items[0].RefileTo(md, &mdGroups[0], items); err != nil {
+		t.Fatal(err)
+	}
+	contents, _ := os.ReadFile(md)
+	if string(contents) != "# notes\n\n  - [ ] bulleted\n  - [ ] one\n        first note\n        second note\n\nafter\n" {
+		t.Errorf("unexpected markdown contents after refile %q", contents)
+	}
+	contents, _ = os.ReadFile(xit)
+	if string(contents) != "[ ] two\n\n[ ] three\n" {
+		t.Errorf("unexpected source contents after refile %q", contents)
+	}
+	if items[0].Line() != 4 || items[1].Line() != 1 || items[2].Line() != 3 {
+		t.Errorf("unexpected lines after refile: %d, %d, %d", items[0].Line(), items[1].Line(), items[2].Line())
+	}
+	if d := items[0].Description(); len(d) != 2 || d[0] != "first note" {
+		t.Errorf("unexpected description after refile %v", d)
+	}
+
+	// and back, within the xit file past the item that followed it
+	if err := items[0].MoveTo(xit, items); err != nil {
+		t.Fatal(err)
+	}
+	if err := items[1].RefileTo(xit, nil, items); err != nil {
+		t.Fatal(err)
+	}
+	contents, _ = os.ReadFile(xit)
+	if string(contents) != "\n[ ] three\n[ ] one\n    first note\n    second note\n[ ] two\n" {
+		t.Errorf("unexpected contents after refiling within the file %q", contents)
+	}
+	if items[0].Line() != 3 || items[1].Line() != 6 || items[2].Line() != 2 {
+		t.Errorf("unexpected lines after refiling within the file: %d, %d, %d", items[0].Line(), items[1].Line(), items[2].Line())
+	}
+	if err := items[0].SetStatus(Ongoing); err != nil {
+		t.Errorf("unexpected error updating refiled item: %s", err)
+	}
+}
+
+func TestInFile(t *testing.T) {
+	abs, err := filepath.Abs("list.xit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := New("./list.xit", 1, "[ ] thing")
+	if !item.InFile("list.xit") || !item.InFile(abs) {
+		t.Errorf("expected %s to be in list.xit, and in %s", item.File(), abs)
+	}
+	if item.InFile("other.xit") {
+		t.Errorf("expected %s not to be in other.xit", item.File())
+	}
+}
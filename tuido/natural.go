@@ -0,0 +1,263 @@
+package tuido
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// naturalDateTags are the tags whose values can be written as
+// natural-language phrases, eg "due:friday" or "active:next month".
+var naturalDateTags = []string{"due", "active"}
+
+// maxPhraseWords is the longest phrase that will be consumed
+// after a `tag:` prefix. eg, "due:in 3 weeks 9am" is four words.
+const maxPhraseWords = 4
+
+// expandNaturalDates replaces natural-language date phrases of the form
+// `tag:phrase` with canonical `#tag=YYYY-MM-DD` tags, or `#tag=YYYY-MM-DDTHH:MM`
+// tags if the phrase includes a time of day.
+//
+// Phrases may span several words ("due:next month", "active:tomorrow 9am").
+// The longest run of words that reads as a date is consumed, and
+// unrecognized phrases are left untouched.
+func expandNaturalDates(s string, now time.Time) string {
+	words := strings.Split(s, " ")
+	expanded := []string{}
+
+	for i := 0; i < len(words); i++ {
+		name, phrase, ok := naturalDatePrefix(words[i])
+		if !ok {
+			expanded = append(expanded, words[i])
+			continue
+		}
+
+		longest := len(words) - i
+		if longest > maxPhraseWords {
+			longest = maxPhraseWords
+		}
+
+		consumed := 0
+		var date time.Time
+		var timed bool
+		for n := longest; n > 0; n-- {
+			candidate := append([]string{phrase}, words[i+1:i+n]...)
+			if d, t, ok := parseDatePhrase(candidate, now); ok {
+				date = d
+				timed = t
+				consumed = n
+				break
+			}
+		}
+
+		if consumed == 0 {
+			expanded = append(expanded, words[i])
+			continue
+		}
+
+		expanded = append(expanded, "#"+Tag{name, formatTagDate(date, timed)}.String())
+		i += consumed - 1
+	}
+
+	return strings.Join(expanded, " ")
+}
+
+// SetDate writes a date-valued tag (eg, #due) to the item, from a
+// phrase like "friday", "tomorrow 9am", "3d" or "2006-01-02".
+func (i *Item) SetDate(name, phrase string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot set %s date", name)
+	}
+
+	date, timed, ok := parseDatePhrase(strings.Fields(phrase), time.Now())
+	if !ok {
+		return fmt.Errorf("could not read %q as a date", phrase)
+	}
+
+	return i.setTag(Tag{name, formatTagDate(date, timed)})
+}
+
+// naturalDatePrefix splits a word like "due:friday" into its
+// tag name and the first word of its phrase.
+func naturalDatePrefix(word string) (string, string, bool) {
+	for _, name := range naturalDateTags {
+		if strings.HasPrefix(word, name+":") && len(word) > len(name)+1 {
+			return name, word[len(name)+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// parseDatePhrase interprets a sequence of words as a date relative to now.
+// Accepted phrases are:
+//   - today, tomorrow
+//   - weekdays (friday, fri), meaning the next one after today
+//   - next week, next month, next year, next friday
+//   - in 3 days, 2 weeks, in 1 month, ...
+//   - shorthand timespans: 3d, 2w, 1M, ...
+//   - eod, eow, eom, eoy (end of day, week, month, year)
+//   - ISO dates: 2006-01-02, 2006-01-02T15:04
+//
+// each optionally followed by a time of day (9am, 5:30pm, 14:00).
+//
+// timed reports whether the phrase resolved to a specific time of day
+// rather than a whole date.
+func parseDatePhrase(words []string, now time.Time) (date time.Time, timed bool, ok bool) {
+	if len(words) == 0 {
+		return time.Time{}, false, false
+	}
+
+	hour, minute, clock := parseTimeOfDay(words[len(words)-1])
+	if clock {
+		words = words[:len(words)-1]
+	}
+
+	date = now
+	if len(words) != 0 {
+		date, timed, ok = parseDateWords(words, now)
+		if !ok {
+			return time.Time{}, false, false
+		}
+	}
+
+	if clock {
+		date = time.Date(date.Year(), date.Month(), date.Day(),
+			hour, minute, 0, 0, date.Location())
+		timed = true
+	}
+
+	return date, timed, true
+}
+
+func parseDateWords(words []string, now time.Time) (date time.Time, timed bool, ok bool) {
+	lower := strings.ToLower(strings.Join(words, " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch lower {
+	case "today", "eod":
+		return today, false, true
+	case "tomorrow", "tmrw":
+		return today.AddDate(0, 0, 1), false, true
+	case "next week":
+		return today.AddDate(0, 0, 7), false, true
+	case "next month":
+		return today.AddDate(0, 1, 0), false, true
+	case "next year":
+		return today.AddDate(1, 0, 0), false, true
+	case "eow":
+		return nextWeekday(today, time.Sunday, true), false, true
+	case "eom":
+		return time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, now.Location()), false, true
+	case "eoy":
+		return time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, now.Location()), false, true
+	}
+
+	if len(words) == 1 {
+		if d, err := ParseDate(words[0]); err == nil {
+			return d, len(words[0]) > len(dateLayout), true
+		}
+		if shorthandRex.MatchString(words[0]) {
+			return toDateFrom(words[0], now), timedUnit(words[0]), true
+		}
+	}
+
+	fields := strings.Fields(lower)
+	if len(fields) == 0 {
+		return time.Time{}, false, false
+	}
+	if fields[0] == "next" && len(fields) == 2 {
+		fields = fields[1:]
+	}
+	if len(fields) == 1 {
+		if day, ok := weekdays[fields[0]]; ok {
+			return nextWeekday(today, day, false), false, true
+		}
+	}
+
+	if fields[0] == "in" && len(fields) == 3 {
+		fields = fields[1:]
+	}
+	if len(fields) == 2 {
+		num, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return time.Time{}, false, false
+		}
+		switch strings.TrimSuffix(fields[1], "s") {
+		case "day":
+			return today.AddDate(0, 0, num), false, true
+		case "week":
+			return today.AddDate(0, 0, 7*num), false, true
+		case "month":
+			return today.AddDate(0, num, 0), false, true
+		case "year":
+			return today.AddDate(num, 0, 0), false, true
+		}
+	}
+
+	return time.Time{}, false, false
+}
+
+// nextWeekday returns the first date after today that falls on day.
+// If inclusive, today itself is also a candidate.
+func nextWeekday(today time.Time, day time.Weekday, inclusive bool) time.Time {
+	diff := (int(day) - int(today.Weekday()) + 7) % 7
+	if diff == 0 && !inclusive {
+		diff = 7
+	}
+	return today.AddDate(0, 0, diff)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var shorthandRex = regexp.MustCompile("^[0-9]+[hdwmyM]$")
+
+var timeOfDayRex = regexp.MustCompile(`^([0-9]{1,2})(?::([0-9]{2}))?(am|pm)?$`)
+
+// parseTimeOfDay reads clock times like "9am", "5:30pm" or "14:00".
+// A bare number is not a time of day - it must have a colon or am/pm.
+func parseTimeOfDay(s string) (hour, minute int, ok bool) {
+	s = strings.ToLower(s)
+	if s == "noon" {
+		return 12, 0, true
+	}
+
+	m := timeOfDayRex.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, 0, false
+	}
+
+	hour, _ = strconv.Atoi(m[1])
+	if m[3] != "" && (hour < 1 || hour > 12) {
+		return 0, 0, false
+	}
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch m[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour != 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+
+	return hour, minute, true
+}
@@ -0,0 +1,109 @@
+package tuido
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandNaturalDates(t *testing.T) {
+	// a monday
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.Local)
+
+	type tc struct {
+		input    string
+		expected string
+	}
+
+	tests := []tc{
+		{"call bob due:friday", "call bob #due=2026-10-23"},
+		{"call bob due:fri", "call bob #due=2026-10-23"},
+		{"call bob due:monday", "call bob #due=2026-10-26"},
+		{"pay rent due:next month", "pay rent #due=2026-11-19"},
+		{"active:tomorrow 9am check the oven", "#active=2026-10-20T09:00 check the oven"},
+		{"standup due:5:30pm", "standup #due=2026-10-19T17:30"},
+		{"nap active:2h", "nap #active=2026-10-19T12:30"},
+		{"ship due:2026-11-03T14:00", "ship #due=2026-11-03T14:00"},
+		{"ship it due:2026-11-03 #release", "ship it #due=2026-11-03 #release"},
+		{"wrap up due:eow", "wrap up #due=2026-10-25"},
+		{"wrap up due:eom", "wrap up #due=2026-10-31"},
+		{"plan due:in 2 weeks", "plan #due=2026-11-02"},
+		{"plan due:3d", "plan #due=2026-10-22"},
+		{"unparsed due:whenever", "unparsed due:whenever"},
+		{"no phrase due: here", "no phrase due: here"},
+	}
+
+	for _, test := range tests {
+		if got := expandNaturalDates(test.input, now); got != test.expected {
+			t.Errorf("expandNaturalDates(%q): expected %q, but found %q", test.input, test.expected, got)
+		}
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	type tc struct {
+		input  string
+		hour   int
+		minute int
+		ok     bool
+	}
+
+	tests := []tc{
+		{"9am", 9, 0, true},
+		{"12am", 0, 0, true},
+		{"12pm", 12, 0, true},
+		{"5:30pm", 17, 30, true},
+		{"14:00", 14, 0, true},
+		{"noon", 12, 0, true},
+		{"9", 0, 0, false},
+		{"13pm", 0, 0, false},
+		{"25:00", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		h, m, ok := parseTimeOfDay(test.input)
+		if h != test.hour || m != test.minute || ok != test.ok {
+			t.Errorf("parseTimeOfDay(%q): expected %d:%d %v, but found %d:%d %v",
+				test.input, test.hour, test.minute, test.ok, h, m, ok)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	type tc struct {
+		input    string
+		expected time.Time
+	}
+
+	tests := []tc{
+		{"2026-10-20", time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)},
+		{"2026-10-20T14:00", time.Date(2026, 10, 20, 14, 0, 0, 0, time.Local)},
+		{"2026-10-20T14:00Z", time.Date(2026, 10, 20, 14, 0, 0, 0, time.UTC)},
+		{"2026-10-20T14:00+02:00", time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		d, err := ParseDate(test.input)
+		if err != nil {
+			t.Errorf("ParseDate(%q): unexpected error %s", test.input, err)
+		}
+		if !d.Equal(test.expected) {
+			t.Errorf("ParseDate(%q): expected %s, but found %s", test.input, test.expected, d)
+		}
+	}
+
+	if _, err := ParseDate("friday"); err == nil {
+		t.Errorf("ParseDate(\"friday\"): expected an error")
+	}
+}
+
+func TestActive(t *testing.T) {
+	past := time.Now().Add(-time.Hour).Format(dateTimeLayout)
+	future := time.Now().Add(time.Hour).Format(dateTimeLayout)
+
+	if !(Item{raw: "[ ] woke #active=" + past}).Active() {
+		t.Errorf("expected item active an hour ago to be active")
+	}
+	if (Item{raw: "[ ] asleep #active=" + future}).Active() {
+		t.Errorf("expected item active in an hour to be inactive")
+	}
+}
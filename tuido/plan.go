@@ -0,0 +1,81 @@
+package tuido
+
+import "time"
+
+// plannedTag marks the day that an item is planned to be worked on,
+// eg #planned=2026-10-19.
+const plannedTag = "planned"
+
+// Planned returns the day the item is planned for, or nil if it is not
+// planned.
+func (i Item) Planned() *time.Time {
+	d, err := ParseDate(i.tagValue(plannedTag))
+	if err != nil {
+		return nil
+	}
+	return &d
+}
+
+// PlannedFor reports whether the item is planned for the local day of t.
+func (i Item) PlannedFor(t time.Time) bool {
+	planned := i.Planned()
+	return planned != nil && sameDay(*planned, t)
+}
+
+// Plan marks the item as planned for the local day of t.
+func (i *Item) Plan(t time.Time) error {
+	if i == nil {
+		return nil
+	}
+	return i.setTag(Tag{plannedTag, formatTagDate(t, false)})
+}
+
+// Unplan removes the item from its planned day.
+func (i *Item) Unplan() error {
+	if i == nil {
+		return nil
+	}
+	return i.removeTag(plannedTag)
+}
+
+// Leftovers returns the unfinished items that were planned for a day
+// before the local day of now.
+func Leftovers(items []*Item, now time.Time) []*Item {
+	today := startOfDay(now)
+	ret := []*Item{}
+	for _, item := range items {
+		if s := item.Satus(); s != Open && s != Ongoing {
+			continue
+		}
+		if planned := item.Planned(); planned != nil && planned.Before(today) {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// PlannedEffort totals the estimates of the items planned for the local
+// day of t, and counts those planned without estimates. Obsolete items
+// are left out, but done ones count, as their work was part of the day.
+func PlannedEffort(items []*Item, t time.Time) (estimated time.Duration, unestimated int) {
+	for _, item := range items {
+		if !item.PlannedFor(t) || item.Satus() == Obsolete {
+			continue
+		}
+		if estimate := item.Estimate(); estimate != nil {
+			estimated += *estimate
+		} else {
+			unestimated++
+		}
+	}
+	return estimated, unestimated
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+func sameDay(a, b time.Time) bool {
+	return startOfDay(a).Equal(startOfDay(b))
+}
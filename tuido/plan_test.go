@@ -0,0 +1,73 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPlannedEffort(t *testing.T) {
+	// a monday
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.Local)
+
+	raws := []string{
+		"[ ] write docs #estimate=1h #planned=2026-10-19",
+		"[x] fix bug #estimate=2h #planned=2026-10-19",
+		"[~] dropped #estimate=3h #planned=2026-10-19",
+		"[ ] unestimated #planned=2026-10-19",
+		"[ ] tomorrow #estimate=30m #planned=2026-10-20",
+		"[@] left over #estimate=30m #planned=2026-10-16",
+		"[x] finished friday #planned=2026-10-16",
+		"[ ] unplanned #estimate=4h",
+	}
+	items := []*Item{}
+	for n, raw := range raws {
+		item := New("list.xit", n+1, raw)
+		items = append(items, &item)
+	}
+
+	estimated, unestimated := PlannedEffort(items, now)
+	if estimated != 3*time.Hour || unestimated != 1 {
+		t.Errorf("expected 3h planned with 1 unestimated, but found %s with %d", estimated, unestimated)
+	}
+
+	leftovers := Leftovers(items, now)
+	if len(leftovers) != 1 || leftovers[0].Text() != "left over #estimate=30m #planned=2026-10-16" {
+		t.Errorf("unexpected leftovers %v", leftovers)
+	}
+
+	if !items[0].PlannedFor(now.Add(12*time.Hour)) || items[4].PlannedFor(now) || items[7].Planned() != nil {
+		t.Errorf("unexpected planned days")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.Local)
+
+	file := filepath.Join(t.TempDir(), "list.xit")
+	contents := "[ ] replan #planned=2026-10-16\n[ ] plan\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+	replan, plan := New(file, 1, "[ ] replan #planned=2026-10-16"), New(file, 2, "[ ] plan")
+
+	if err := replan.Plan(now); err != nil {
+		t.Fatal(err)
+	}
+	if err := plan.Plan(now); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(file)
+	if string(got) != "[ ] replan #planned=2026-10-19\n[ ] plan #planned=2026-10-19\n" {
+		t.Errorf("unexpected contents after planning %q", got)
+	}
+
+	if err := replan.Unplan(); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(file)
+	if string(got) != "[ ] replan\n[ ] plan #planned=2026-10-19\n" {
+		t.Errorf("unexpected contents after unplanning %q", got)
+	}
+}
@@ -0,0 +1,167 @@
+package tuido
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Grouping is a way of breaking down an effort report.
+type Grouping string
+
+const (
+	ByTag     Grouping = "tag"
+	ByProject Grouping = "project"
+	ByFile    Grouping = "file"
+	ByWeek    Grouping = "week"
+)
+
+// Groupings are the available report breakdowns, in report order.
+var Groupings = []Grouping{ByTag, ByProject, ByFile, ByWeek}
+
+// ungrouped names the group of items that have no tag, project, or date.
+const ungrouped = "(none)"
+
+// trackingTags are tags that tuido interprets itself, and which are
+// left out of the by-tag breakdown.
+var trackingTags = map[string]bool{
+	"estimate": true, "spent": true, "due": true, "active": true,
+	"repeat": true, "completed": true, "created": true, "lastDone": true, "zzz": true,
+	"planned": true,
+}
+
+// EffortStats compares estimated and spent time across a group of
+// items. Only items with an #estimate or time #spent are counted.
+type EffortStats struct {
+	Group string
+	Items int
+
+	// Estimate and Spent are the totals across the group
+	Estimate time.Duration
+	Spent    time.Duration
+
+	// Finished counts the done items with estimates, and FinishedEstimate
+	// and FinishedSpent total their time. Accuracy is judged by these, as
+	// unfinished items have yet to spend their estimates.
+	Finished         int
+	FinishedEstimate time.Duration
+	FinishedSpent    time.Duration
+
+	// Overruns counts the items, done or not, that have spent more than
+	// their estimates.
+	Overruns int
+}
+
+// Accuracy is the ratio of time spent to time estimated for finished
+// items: over 1 when work takes longer than expected. ok is false if
+// there are no finished items with estimates.
+func (s EffortStats) Accuracy() (ratio float64, ok bool) {
+	if s.FinishedEstimate == 0 {
+		return 0, false
+	}
+	return float64(s.FinishedSpent) / float64(s.FinishedEstimate), true
+}
+
+func (s *EffortStats) add(item *Item) {
+	estimate := item.Estimate()
+	spent := item.Spent()
+
+	s.Items++
+	s.Spent += spent
+	if estimate == nil {
+		return
+	}
+	s.Estimate += *estimate
+	if spent > *estimate {
+		s.Overruns++
+	}
+	if item.Satus() == Checked {
+		s.Finished++
+		s.FinishedEstimate += *estimate
+		s.FinishedSpent += spent
+	}
+}
+
+// tracked reports whether the item has an estimate or time spent.
+func tracked(item *Item) bool {
+	return item.Estimate() != nil || item.Spent() > 0
+}
+
+// EffortTotal returns the effort stats across all items.
+func EffortTotal(items []*Item) EffortStats {
+	total := EffortStats{Group: "total"}
+	for _, item := range items {
+		if tracked(item) {
+			total.add(item)
+		}
+	}
+	return total
+}
+
+// EffortReport breaks down the effort stats of items by group, in
+// order of group name. Items in several groups, eg with two tags, are
+// counted in each.
+func EffortReport(items []*Item, by Grouping) []EffortStats {
+	groups := map[string]*EffortStats{}
+
+	for _, item := range items {
+		if !tracked(item) {
+			continue
+		}
+		for _, name := range groupsOf(item, by) {
+			if groups[name] == nil {
+				groups[name] = &EffortStats{Group: name}
+			}
+			groups[name].add(item)
+		}
+	}
+
+	report := []EffortStats{}
+	for _, s := range groups {
+		report = append(report, *s)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		// the ungrouped items come last
+		if (report[i].Group == ungrouped) != (report[j].Group == ungrouped) {
+			return report[j].Group == ungrouped
+		}
+		return report[i].Group < report[j].Group
+	})
+	return report
+}
+
+// groupsOf returns the names of the groups that item falls in.
+func groupsOf(item *Item, by Grouping) []string {
+	names := []string{}
+
+	switch by {
+	case ByTag:
+		for _, tag := range item.Tags() {
+			if !trackingTags[tag.Name()] {
+				names = append(names, "#"+tag.Name())
+			}
+		}
+	case ByProject:
+		for _, p := range item.Projects() {
+			names = append(names, "+"+p)
+		}
+	case ByFile:
+		names = append(names, filepath.Clean(item.file))
+	case ByWeek:
+		// work is counted in the week it was finished, or else begun
+		date := item.Completed()
+		if date == nil || date.IsZero() {
+			date = item.Created()
+		}
+		if date != nil && !date.IsZero() {
+			year, week := date.ISOWeek()
+			names = append(names, fmt.Sprintf("%d-W%02d", year, week))
+		}
+	}
+
+	if len(names) == 0 {
+		return []string{ungrouped}
+	}
+	return names
+}
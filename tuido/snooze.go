@@ -0,0 +1,157 @@
+package tuido
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type snoozeKind string
+
+const (
+	fibonacciSnooze   snoozeKind = "fibonacci"
+	linearSnooze      snoozeKind = "linear"
+	fixedSnooze       snoozeKind = "fixed"
+	nextWeekdaySnooze snoozeKind = "next-weekday"
+	nextMondaySnooze  snoozeKind = "next-monday"
+)
+
+// SnoozePolicy decides how far into the future the nth
+// consecutive snooze of an item hides it.
+type SnoozePolicy struct {
+	kind snoozeKind
+	// span is a shorthand timespan (eg, 1d) that scales fibonacci
+	// and linear policies, and is the length of fixed snoozes.
+	span string
+}
+
+// DefaultSnoozePolicy parks items for 1, 2, 3, 5, 8, ... days.
+var DefaultSnoozePolicy = SnoozePolicy{kind: fibonacciSnooze, span: "1d"}
+
+// ParseSnoozePolicy reads a policy of the form `kind[:span]`, where kind is one of
+//   - fibonacci: fib(n) spans (1, 2, 3, 5, 8, ...)
+//   - linear: n spans (1, 2, 3, 4, ...)
+//   - fixed: one span each time
+//   - next-weekday: until the next monday-friday
+//   - next-monday: until next monday
+//
+// and span is a shorthand timespan, defaulting to 1d. eg, "fixed:4h".
+func ParseSnoozePolicy(s string) (SnoozePolicy, error) {
+	kind, span, found := strings.Cut(strings.TrimSpace(s), ":")
+	if !found {
+		span = "1d"
+	}
+	if !shorthandRex.MatchString(span) {
+		return SnoozePolicy{}, fmt.Errorf("invalid snooze span %q in policy %q", span, s)
+	}
+
+	switch snoozeKind(kind) {
+	case fibonacciSnooze, linearSnooze, fixedSnooze, nextWeekdaySnooze, nextMondaySnooze:
+		return SnoozePolicy{kind: snoozeKind(kind), span: span}, nil
+	}
+
+	return SnoozePolicy{}, fmt.Errorf("unknown snooze policy %q", s)
+}
+
+func (p SnoozePolicy) String() string {
+	return fmt.Sprintf("%s:%s", p.kind, p.span)
+}
+
+// until returns the wake-up time for the count-th consecutive snooze, and
+// whether that time wants a time of day (ie, is finer than a whole date).
+func (p SnoozePolicy) until(count int, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch p.kind {
+	case nextWeekdaySnooze:
+		next := today.AddDate(0, 0, 1)
+		for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, false
+	case nextMondaySnooze:
+		return nextWeekday(today, time.Monday, false), false
+	}
+
+	num, _ := strconv.Atoi(p.span[:len(p.span)-1])
+	unit := p.span[len(p.span)-1:]
+
+	switch p.kind {
+	case fibonacciSnooze:
+		num *= fib(count)
+	case linearSnooze:
+		num *= count
+	}
+
+	return toDateFrom(fmt.Sprint(num)+unit, now), timedUnit(p.span)
+}
+
+// Snooze hides the item according to the default, fibonacci, policy.
+func (i *Item) Snooze() error {
+	return i.SnoozeWith(DefaultSnoozePolicy)
+}
+
+// SnoozeWith hides the item by pushing its #active date into the
+// future according to p, and increments its #zzz snooze count.
+func (i *Item) SnoozeWith(p SnoozePolicy) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot snooze")
+	}
+
+	count := i.SnoozeCount()
+	count++
+
+	until, timed := p.until(count, time.Now())
+	return i.snoozeUntil(until, timed, count)
+}
+
+// SnoozeFor hides the item until the date described by phrase,
+// which may be a shorthand timespan (3d, 4h) or any phrase accepted
+// by natural-language date entry (friday, tomorrow 9am).
+func (i *Item) SnoozeFor(phrase string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot snooze")
+	}
+
+	err := i.SetDate("active", phrase)
+	if err != nil {
+		return err
+	}
+	return i.setTag(Tag{"zzz", fmt.Sprint(i.SnoozeCount() + 1)})
+}
+
+func (i *Item) snoozeUntil(until time.Time, timed bool, count int) error {
+	err := i.setTag(Tag{"active", formatTagDate(until, timed)})
+	if err != nil {
+		return err
+	}
+	return i.setTag(Tag{"zzz", fmt.Sprint(count)})
+}
+
+// Unsnooze wakes the item immediately by removing its #active
+// date, and resets its snooze count by removing #zzz.
+func (i *Item) Unsnooze() error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot unsnooze")
+	}
+
+	err := i.removeTag("active")
+	if err != nil {
+		return err
+	}
+	return i.removeTag("zzz")
+}
+
+// SnoozeCount returns the number of consecutive times the item has
+// been snoozed, as recorded in its #zzz tag.
+func (i Item) SnoozeCount() int {
+	for _, tag := range i.Tags() {
+		if tag.name == "zzz" {
+			count, _ := strconv.Atoi(tag.value)
+			return count
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,52 @@
+package tuido
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozePolicy(t *testing.T) {
+	// a monday
+	now := time.Date(2026, 10, 19, 10, 30, 0, 0, time.Local)
+
+	type tc struct {
+		policy   string
+		count    int
+		expected string
+	}
+
+	tests := []tc{
+		{"fibonacci", 1, "2026-10-20"},
+		{"fibonacci", 4, "2026-10-24"},
+		{"linear", 4, "2026-10-23"},
+		{"linear:1w", 2, "2026-11-02"},
+		{"fixed:3d", 7, "2026-10-22"},
+		{"fixed:4h", 2, "2026-10-19T14:30"},
+		{"next-weekday", 1, "2026-10-20"},
+		{"next-monday", 1, "2026-10-26"},
+	}
+
+	for _, test := range tests {
+		p, err := ParseSnoozePolicy(test.policy)
+		if err != nil {
+			t.Errorf("ParseSnoozePolicy(%q): unexpected error %s", test.policy, err)
+			continue
+		}
+		if got := formatTagDate(p.until(test.count, now)); got != test.expected {
+			t.Errorf("%s snooze #%d: expected %s, but found %s", test.policy, test.count, test.expected, got)
+		}
+	}
+
+	// friday snoozes to next-weekday skip the weekend
+	friday := time.Date(2026, 10, 23, 10, 30, 0, 0, time.Local)
+	p, _ := ParseSnoozePolicy("next-weekday")
+	if got := formatTagDate(p.until(1, friday)); got != "2026-10-26" {
+		t.Errorf("next-weekday from friday: expected 2026-10-26, but found %s", got)
+	}
+
+	for _, bad := range []string{"forever", "fixed:3", "linear:xd"} {
+		if _, err := ParseSnoozePolicy(bad); err == nil {
+			t.Errorf("ParseSnoozePolicy(%q): expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tuido
+
+import "strings"
+
+// Subtasks returns the items nested beneath the item in its file: the
+// following items that are indented further than it, up to the first
+// blank line or line indented no further than the item, as in nested
+// markdown lists.
+//
+// others are the in-memory items parsed from disk, which the subtasks
+// are taken from.
+func (i Item) Subtasks(others []*Item) []*Item {
+	lines, err := Source(i.file)
+	if err != nil || i.line < 1 || i.line > len(lines) || lines[i.line-1] != i.raw {
+		return nil
+	}
+
+	byLine := map[int]*Item{}
+	for _, other := range others {
+		if other.file == i.file {
+			byLine[other.line] = other
+		}
+	}
+
+	depth := indentation(i.raw)
+	subtasks := []*Item{}
+	for n := i.line + 1; n <= len(lines); n++ {
+		l := lines[n-1]
+		if strings.TrimSpace(l) == "" || indentation(l) <= depth {
+			break
+		}
+		if sub, ok := byLine[n]; ok {
+			subtasks = append(subtasks, sub)
+		}
+	}
+	return subtasks
+}
+
+// indentation measures the leading whitespace of a line, counting tabs
+// as four spaces.
+func indentation(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
@@ -0,0 +1,55 @@
+package tuido
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSubtasks(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.md")
+	contents := "- [ ] parent\n" +
+		"    a note on the parent\n" +
+		"  - [ ] first child\n" +
+		"    - [x] grandchild\n" +
+		"  - [ ] second child\n" +
+		"- [ ] sibling\n" +
+		"  - [ ] sibling's child\n"
+	if err := os.WriteFile(file, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*Item{}
+	for line, raw := range map[int]string{
+		1: "- [ ] parent", 3: "  - [ ] first child", 4: "    - [x] grandchild",
+		5: "  - [ ] second child", 6: "- [ ] sibling", 7: "  - [ ] sibling's child",
+	} {
+		item := New(file, line, raw)
+		items = append(items, &item)
+	}
+	find := func(line int) *Item {
+		for _, item := range items {
+			if item.line == line {
+				return item
+			}
+		}
+		return nil
+	}
+
+	subtasks := find(1).Subtasks(items)
+	if len(subtasks) != 3 {
+		t.Fatalf("expected 3 subtasks, but found %d", len(subtasks))
+	}
+	for n, line := range []int{3, 4, 5} {
+		if subtasks[n].line != line {
+			t.Errorf("expected subtask %d on line %d, but found line %d", n, line, subtasks[n].line)
+		}
+	}
+
+	if subtasks := find(3).Subtasks(items); len(subtasks) != 1 || subtasks[0].line != 4 {
+		t.Errorf("expected the grandchild as the only subtask of the first child, but found %v", subtasks)
+	}
+	if subtasks := find(5).Subtasks(items); len(subtasks) != 0 {
+		t.Errorf("expected no subtasks of the second child, but found %v", subtasks)
+	}
+}
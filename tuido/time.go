@@ -6,6 +6,49 @@ import (
 	"time"
 )
 
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04"
+)
+
+// tagDateLayouts are the accepted formats for date-valued tags
+// like #due and #active. Values without an explicit UTC offset
+// are read in the local timezone.
+var tagDateLayouts = []string{
+	"2006-01-02T15:04Z07:00",
+	dateTimeLayout,
+	dateLayout,
+}
+
+// ParseDate reads a date-valued tag like `2006-01-02`, `2006-01-02T15:04`,
+// or `2006-01-02T15:04+02:00`.
+func ParseDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range tagDateLayouts {
+		var t time.Time
+		t, err = time.ParseInLocation(layout, s, time.Local)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// formatTagDate renders t as the value of a date-valued tag.
+// The time of day is only included if timed.
+func formatTagDate(t time.Time, timed bool) string {
+	if timed {
+		return t.Local().Format(dateTimeLayout)
+	}
+	return t.Local().Format(dateLayout)
+}
+
+// timedUnit reports whether a shorthand timespan like 3h or 20m is
+// finer than a day, and so wants a time of day when converted to a date.
+func timedUnit(dStr string) bool {
+	return len(dStr) > 0 && (dStr[len(dStr)-1] == 'h' || dStr[len(dStr)-1] == 'm')
+}
+
 // expandDateShorthands takes an input string (a user supplied tuido)
 // and expands its date shorthads via a regex matcher.
 //
@@ -14,10 +57,18 @@ import (
 //  - "a5w" -> "#active=[datestring for 5 weeks from now]" (hide until 5 weeks)
 //  - "e25m" -> "#estimate=25m" (estimate 25 minutes task time)
 //  - "d7d" -> "#due=[datestring for 7 days from now]" (set a due date)
+//  - "due:friday" -> "#due=[datestring for the coming friday]" (see parseDatePhrase)
 func expandDateShorthands(s string) string {
+	s = expandNaturalDates(s, time.Now())
 	return rex.ReplaceAllStringFunc(s, repl)
 }
 
+// ExpandShorthands returns s as it would be written to disk by SetText,
+// with all date and duration shorthands expanded into tags.
+func ExpandShorthands(s string) string {
+	return expandDateShorthands(s)
+}
+
 var rex regexp.Regexp = *regexp.MustCompile("[r,e,a,d][0-9]+[h,d,w,m,y,M]")
 
 func repl(s string) string {
@@ -40,7 +91,7 @@ func repl(s string) string {
 	}
 
 	t := toDate(s[1:])
-	ret += t.Format("2006-01-02")
+	ret += formatTagDate(t, timedUnit(s[1:]))
 
 	return ret
 }
@@ -55,8 +106,12 @@ func repl(s string) string {
 //
 // [ ] #test #parsing
 func toDate(dStr string) time.Time {
+	return toDateFrom(dStr, time.Now())
+}
+
+// toDateFrom is toDate, relative to the supplied time rather than now.
+func toDateFrom(dStr string, t time.Time) time.Time {
 	// fmt.Println("toDate(" + dStr + ")")
-	t := time.Now()
 
 	num, err := strconv.Atoi(dStr[:len(dStr)-1])
 
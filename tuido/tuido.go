@@ -2,19 +2,14 @@ package tuido
 
 import (
 	"bufio"
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
-	"github.com/alecthomas/chroma/v2/lexers"
-	"github.com/alecthomas/chroma/v2/quick"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/nilock/tuido/utils"
 )
 
 type status string
@@ -105,21 +100,26 @@ func (i *Item) SetStatus(s status) error {
 		if repeat != nil {
 			i.setTag(Tag{
 				name:  "active",
-				value: time.Now().Add(*repeat).Format("2006-01-02"),
+				value: formatTagDate(time.Now().Add(*repeat), timedUnit(i.tagValue("repeat"))),
 			})
 			i.setTag(Tag{
 				name:  "lastDone",
-				value: time.Now().Format("2006-01-02"),
+				value: formatTagDate(time.Now(), false),
 			})
 
 			// prevent fall-through - we no longer want this to be
 			// marked "done". It's only been pushed into the future
 			return nil
 		}
-		// [ ] add #completed=[currentDate] if s == Checked?
 	}
 
-	newRaw := i.scrap() + s.String() + " " + i.Text()
+	// record completion dates, and forget them if an item is reopened
+	txt := removeTagText(i.Text(), "completed")
+	if s == Checked {
+		txt = setTagText(txt, Tag{"completed", formatTagDate(time.Now(), false)})
+	}
+
+	newRaw := i.scrap() + s.String() + " " + txt
 
 	err := fileInsert(i.file, i.line, i.raw, newRaw)
 	if err != nil {
@@ -130,30 +130,23 @@ func (i *Item) SetStatus(s status) error {
 	return nil
 }
 
-func (i *Item) IncrementTimeSpent(seconds int) {
+// IncrementTimeSpent adds seconds to the item's #spent tag. A #spent tag
+// that can't be parsed is left as it is, and reported as an error.
+func (i *Item) IncrementTimeSpent(seconds int) error {
 	if i == nil {
-		return
+		return fmt.Errorf("item is nil - cannot add time spent")
 	}
-	previouslySpent := 0.0
 
-	for _, t := range i.Tags() {
-		if t.name == "spent" {
-			var err error
-
-			previouslySpent, err = strconv.ParseFloat(t.value, 64)
-			if err != nil {
-				return
-			}
-			break
+	spent := time.Duration(0)
+	if value := i.tagValue("spent"); value != "" {
+		var err error
+		if spent, err = ParseEffort(value); err != nil {
+			return fmt.Errorf("cannot add %ds to #spent=%s: %w", seconds, value, err)
 		}
 	}
-	asMinutes := float64(seconds) / 60
-
-	asStr := fmt.Sprintf("%.2f", previouslySpent+asMinutes)
-
-	i.setTag(Tag{
+	return i.setTag(Tag{
 		name:  "spent",
-		value: asStr,
+		value: formatSpent(spent + time.Duration(seconds)*time.Second),
 	})
 }
 
@@ -162,7 +155,7 @@ func (i *Item) IncrementTimeSpent(seconds int) {
 //
 // If the disk write fails, the in-memory update is abandoned.
 func (i *Item) SetText(t string) error {
-	t = expandDateShorthands(t)
+	t = ExpandShorthands(t)
 
 	if i == nil {
 		return fmt.Errorf("item is nil - cannot update text")
@@ -181,52 +174,25 @@ func (i *Item) SetText(t string) error {
 //
 // The returned integer is the line number of the item's text inside the returned context.
 func (i *Item) GetContext(height int) (string, int) {
-	fileBytes, err := exec.Command("cat", i.file).CombinedOutput()
-
+	// lines are counted from 0, and i.line from 1
+	first := max(0, i.line-1-(height/2)+1)
+	lines, err := Highlighted(i.file, first, i.line-1+(height/2))
 	if err != nil {
 		return err.Error(), 0
 	}
 
-	// colorize the file contents
-	buf := new(bytes.Buffer)
-	quick.Highlight(buf, string(fileBytes), lexers.Match(i.file).Config().Name, utils.GetTerminalColorSupport(), "monokai")
-
-	lines := strings.Split(string(buf.Bytes()), "\n")
-	lines = append([]string{""}, lines...)
-
-	first := i.line - (height / 2) + 1
-	if first < 0 {
-		first = 0
-	}
-	last := i.line + (height / 2) - 1
-	if last > len(lines)-1 {
-		last = len(lines) - 1
+	n := i.line - 1 - first
+	if n < 0 || n >= len(lines) {
+		return strings.Join(lines, "\n"), 0
 	}
 
-	preItemLines := strings.Join(lines[first:i.line], "\n")
-	item := lines[i.line]
-	postItemLines := strings.Join(lines[i.line+1:last], "\n")
-
-	item = lipgloss.NewStyle().Bold(true).Italic(true).Render(item) // not working - clobbered by styles from chroma
-
-	return preItemLines + "\n" + item + "\n" + postItemLines, i.line - first
-}
-
-func (i *Item) Snooze() error {
-	if i == nil {
-		return fmt.Errorf("item is nil - cannot snooze")
+	plain, err := Source(i.file)
+	if err == nil && i.line-1 < len(plain) {
+		// chroma's colors would clobber any styling of the highlighted line
+		lines[n] = lipgloss.NewStyle().Bold(true).Italic(true).Render(plain[i.line-1])
 	}
 
-	count := i.snoozeCount()
-	count++
-
-	// i.set("active", time.Now() + fib(count) days)
-	i.setTag(Tag{
-		"active",
-		time.Now().Add(time.Hour * time.Duration(24*fib(count))).Format("2006-01-02"),
-	})
-	// i.set("zzz", count)
-	return i.setTag(Tag{"zzz", fmt.Sprint(count)})
+	return strings.Join(lines, "\n"), n
 }
 
 // Escalate increases the "importance" of an item by prefixing it
@@ -282,43 +248,99 @@ func fib(n int) int {
 	return fib(n-1) + fib(n-2)
 }
 
-func (i *Item) snoozeCount() int {
-	for _, tag := range i.Tags() {
-		if tag.name == "zzz" {
-			count, _ := strconv.Atoi(tag.value)
-			return count
-		}
+// SetTag writes a #name=value tag to the item, replacing the value of
+// an existing tag with the same name. An empty value writes a bare #name.
+func (i *Item) SetTag(name, value string) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot set tag")
 	}
-
-	return 0
+	return i.setTag(Tag{name, value})
 }
 
 // setTag replaces the value of an existing tag, or appends a new tag.
 func (i *Item) setTag(t Tag) error {
-	// replace existing value, if exists
-	for _, tag := range i.Tags() {
+	return i.SetText(setTagText(i.Text(), t))
+}
+
+// removeTag deletes every instance of the named tag from the item's text.
+func (i *Item) removeTag(name string) error {
+	txt := removeTagText(i.Text(), name)
+	if txt == i.Text() {
+		return nil
+	}
+	return i.SetText(txt)
+}
+
+// setTagText replaces the value of an existing tag in txt, or appends a new tag.
+func setTagText(txt string, t Tag) string {
+	for _, tag := range Tags(txt) {
 		if tag.name == t.name {
-			txt := strings.Replace(i.Text(), tag.String(), t.String(), 1)
+			return strings.Replace(txt, tag.String(), t.String(), 1)
+		}
+	}
 
-			return i.SetText(txt)
+	return txt + " #" + t.String()
+}
+
+// removeTagText deletes every instance of the named tag from txt.
+func removeTagText(txt string, name string) string {
+	words := strings.Split(txt, " ")
+	kept := []string{}
+
+	for _, w := range words {
+		if strings.HasPrefix(w, "#") && len(w) > 1 && newTag(w).name == name {
+			continue
 		}
+		kept = append(kept, w)
 	}
 
-	// else, append new tag
-	txt := i.Text() + " #" + t.String()
-	return i.SetText(txt)
+	return strings.Join(kept, " ")
 }
 
 // fileInsert replaces the lineNumberth line of file with updated, as long
 // it finds that the current contents of that line are as expected.
 func fileInsert(file string, lineNumber int, expected string, updated string) error {
-	f, err := os.OpenFile(file, os.O_RDWR, os.ModeExclusive)
-	defer f.Close()
+	return fileRewrite(file, func(lines []string) ([]string, error) {
+		if err := expectLine(lines, lineNumber, expected); err != nil {
+			return nil, err
+		}
+
+		lines[lineNumber] = updated
+		return lines, nil
+	})
+}
+
+// fileRemoveLines deletes the lines of file starting at lineNumber, as
+// long as it finds that their current contents are as expected.
+func fileRemoveLines(file string, lineNumber int, expected []string) error {
+	return fileRewrite(file, func(lines []string) ([]string, error) {
+		for n, e := range expected {
+			if err := expectLine(lines, lineNumber+n, e); err != nil {
+				return nil, err
+			}
+		}
+
+		return append(lines[:lineNumber], lines[lineNumber+len(expected):]...), nil
+	})
+}
+
+func expectLine(lines []string, lineNumber int, expected string) error {
+	if lineNumber < 1 || lineNumber >= len(lines) || lines[lineNumber] != expected {
+		return fmt.Errorf("todo no longer in expected location, or changed on disk...")
+	}
+	return nil
+}
 
+// fileRewrite reads file into lines, offset by a blank line so that
+// lines[n] is the nth line of the file, and writes back the lines
+// returned by edit. If edit returns an error, the file is left untouched.
+func fileRewrite(file string, edit func(lines []string) ([]string, error)) error {
+	f, err := os.OpenFile(file, os.O_RDWR, os.ModeExclusive)
 	if err != nil {
-		fmt.Printf("error opening file for setStatus: %s", err)
 		return err
 	}
+	defer f.Close()
+
 	scanner := bufio.NewScanner(f)
 
 	lines := []string{""} // blank line to offset
@@ -327,17 +349,20 @@ func fileInsert(file string, lineNumber int, expected string, updated string) er
 		lines = append(lines, scanner.Text())
 	}
 
-	if lines[lineNumber] != expected {
-		fmt.Printf("error finding todo: %s != %s", lines[lineNumber], expected)
-		return fmt.Errorf("todo no longer in expected location, or changed on disk...")
+	lines, err = edit(lines)
+	if err != nil {
+		return err
 	}
 
 	_, err = f.Seek(0, 0)
 	if err != nil {
-		fmt.Printf("seek error: %s", err)
+		return err
+	}
+	// the updated contents may be shorter than the original
+	err = f.Truncate(0)
+	if err != nil {
+		return err
 	}
-
-	lines[lineNumber] = updated
 
 	for _, l := range lines[1:] {
 		_, err := f.Write([]byte(l + "\n"))
@@ -379,9 +404,28 @@ func (i Item) Tags() []Tag {
 	return Tags(i.Text())
 }
 
+// Projects returns the item's +projects.
+func (i Item) Projects() []string {
+	return Projects(i.Text())
+}
+
+// Contexts returns the item's @contexts.
+func (i Item) Contexts() []string {
+	return Contexts(i.Text())
+}
+
+// tagValue returns the value of the item's first tag with the given
+// name, or "" if there is no such tag.
+func (i Item) tagValue(name string) string {
+	return tagValue(i.Text(), name)
+}
+
 // Active returns the "active" status for snoozed items.
-// Items with `active` tags later than the current date will not
+// Items with `active` tags later than the current time will not
 // be shown in the regular view. Defaults to true.
+//
+// Date-only tags become active at local midnight, and tags with
+// a time of day (#active=2006-01-02T15:04) become active at that time.
 func (i Item) Active() bool {
 	for _, t := range i.Tags() {
 		if t.name == "active" {
@@ -391,6 +435,17 @@ func (i Item) Active() bool {
 	return true
 }
 
+// ActiveDate returns the time that a snoozed item wakes up,
+// or nil if the item has no #active tag.
+func (i Item) ActiveDate() *time.Time {
+	for _, t := range i.Tags() {
+		if t.name == "active" {
+			return parseTagDate(t)
+		}
+	}
+	return nil
+}
+
 // Importance returns the number of leading '!'s in
 // the item's Text.
 func (i Item) Importance() int {
@@ -416,10 +471,10 @@ func (i Item) Created() *time.Time {
 		}
 	}
 	for c := range i.file {
-		l := len("2006-01-02")
+		l := len(dateLayout)
 		if c+l < len(i.file) {
 			sStr := i.file[c : c+l]
-			if d, err := time.Parse("2006-01-02", sStr); err == nil {
+			if d, err := time.ParseInLocation(dateLayout, sStr, time.Local); err == nil {
 				return &d
 			}
 		}
@@ -437,6 +492,25 @@ func (i Item) Repeat() *time.Duration {
 	return nil
 }
 
+// Completed returns the date an item was checked off,
+// or nil if it has no #completed tag.
+func (i Item) Completed() *time.Time {
+	for _, t := range i.Tags() {
+		if t.name == "completed" {
+			return parseTagDate(t)
+		}
+	}
+	return nil
+}
+
+// SetCompleted records t as the date the item was checked off.
+func (i *Item) SetCompleted(t time.Time) error {
+	if i == nil {
+		return fmt.Errorf("item is nil - cannot set completion date")
+	}
+	return i.setTag(Tag{"completed", formatTagDate(t, false)})
+}
+
 func (i Item) Due() *time.Time {
 	for _, t := range i.Tags() {
 		if t.name == "due" { //  [ ]!  make a const enum somewhere - appTags or something
@@ -447,7 +521,7 @@ func (i Item) Due() *time.Time {
 }
 
 func parseTagDate(t Tag) *time.Time {
-	ret, err := time.Parse("2006-01-02", t.value)
+	ret, err := ParseDate(t.value)
 	if err != nil {
 		// panic(err)
 	}
@@ -522,7 +596,7 @@ func New(
 		}
 
 		if fInfo.IsDir() {
-			file = filepath.Join(file, time.Now().Format("2006-01-02")+".xit") // xit, md, tbd
+			file = filepath.Join(file, time.Now().Format(dateLayout)+".xit") // xit, md, tbd
 		}
 
 		f, err := os.OpenFile(file, os.O_APPEND|os.O_RDWR|os.O_CREATE, 0777)
@@ -566,6 +640,33 @@ func Tags(s string) []Tag {
 	return tags
 }
 
+// Projects returns the names of the GTD style projects in s, which are
+// written as words with a leading plus sign. eg, "+release" is project "release".
+func Projects(s string) []string {
+	return sigilWords(s, '+')
+}
+
+// Contexts returns the names of the GTD style contexts in s, which are
+// written as words with a leading at sign. eg, "@phone" is context "phone".
+func Contexts(s string) []string {
+	return sigilWords(s, '@')
+}
+
+// sigilWords returns the words in s that begin with sigil followed by
+// a letter, with the sigil removed.
+func sigilWords(s string, sigil rune) []string {
+	words := []string{}
+
+	for _, token := range strings.Split(s, " ") {
+		runes := []rune(token)
+		if len(runes) > 1 && runes[0] == sigil && unicode.IsLetter(runes[1]) {
+			words = append(words, string(runes[1:]))
+		}
+	}
+
+	return words
+}
+
 type Tag struct {
 	name  string
 	value string
@@ -574,6 +675,9 @@ type Tag struct {
 func (t Tag) Name() string {
 	return t.name
 }
+func (t Tag) Value() string {
+	return t.value
+}
 func (t Tag) String() string {
 	if t.value != "" {
 		return fmt.Sprintf("%s=%s", t.name, t.value)
@@ -1,7 +1,10 @@
 package tuido
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewTag(t *testing.T) {
@@ -83,3 +86,54 @@ func TestImportance(t *testing.T) {
 		}
 	}
 }
+
+func TestProjectsAndContexts(t *testing.T) {
+	item := Item{raw: "[ ] call +release manager @phone about +1 bob@example.com @ work #due=2026-10-20"}
+
+	projects := item.Projects()
+	if len(projects) != 1 || projects[0] != "release" {
+		t.Errorf("expected projects [release], but found %v", projects)
+	}
+
+	contexts := item.Contexts()
+	if len(contexts) != 1 || contexts[0] != "phone" {
+		t.Errorf("expected contexts [phone], but found %v", contexts)
+	}
+}
+
+func TestSetStatusCompleted(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "todo.xit")
+	if err := os.WriteFile(file, []byte("[ ] write tests\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	item := New(file, 1, "[ ] write tests")
+	today := time.Now().Format(dateLayout)
+
+	if err := item.SetStatus(Checked); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "[x] write tests #completed=" + today; item.raw != expected {
+		t.Errorf("expected %q, but found %q", expected, item.raw)
+	}
+
+	if err := item.SetStatus(Open); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "[ ] write tests"; item.raw != expected {
+		t.Errorf("expected %q, but found %q", expected, item.raw)
+	}
+
+	onDisk, _ := os.ReadFile(file)
+	if string(onDisk) != "[ ] write tests\n" {
+		t.Errorf("expected file contents to be restored, but found %q", onDisk)
+	}
+
+	// a completion date can also be recorded directly, eg when archiving
+	if err := item.SetCompleted(time.Date(2026, 10, 16, 9, 0, 0, 0, time.Local)); err != nil {
+		t.Fatal(err)
+	}
+	if c := item.Completed(); c == nil || c.Format(dateLayout) != "2026-10-16" {
+		t.Errorf("expected completion on 2026-10-16, but found %v", c)
+	}
+}